@@ -2,7 +2,8 @@ package fcm
 
 // easyjson:json
 type sendRequest struct {
-	Message *Message `json:"message"`
+	ValidateOnly bool     `json:"validate_only,omitempty"`
+	Message      *Message `json:"message"`
 }
 
 // There is not clear doc for the API sendResponse
@@ -10,6 +11,7 @@ type sendRequest struct {
 // https://firebase.google.com/docs/reference/fcm/rest/v1/projects.messages/send
 // easyjson:json
 type sendResponse struct {
+	Name  string         `json:"name,omitempty"`
 	Error *responseError `json:"error,omitempty"`
 }
 
@@ -24,10 +26,18 @@ type responseError struct {
 }
 
 // Details could be different type of structs,
-// we are interested in one that has errorCode.
+// we are interested in one that has errorCode
+// and google.rpc.BadRequest one that has fieldViolations.
 // easyjson:json
 type errorDetails struct {
-	ErrorCode errorCode `json:"errorCode,omitempty"`
+	ErrorCode       errorCode        `json:"errorCode,omitempty"`
+	FieldViolations []fieldViolation `json:"fieldViolations,omitempty"`
+}
+
+// easyjson:json
+type fieldViolation struct {
+	Field       string `json:"field,omitempty"`
+	Description string `json:"description,omitempty"`
 }
 
 // Possible error codes are listed here
@@ -41,4 +51,32 @@ const (
 	// App instance was unregistered from FCM for HTTP error code = 404
 	// This usually means that the token used is no longer valid (i.e. expired) and a new one must be used.
 	errorCodeUnregistered errorCode = "UNREGISTERED"
+
+	// Request parameters were invalid for HTTP error code = 400.
+	// The field violations point to the invalid field, e.g. "message.token".
+	errorCodeInvalidArgument errorCode = "INVALID_ARGUMENT"
+
+	// The authenticated sender ID is different from the sender ID for the registration token
+	// for HTTP error code = 403.
+	errorCodeSenderIDMismatch errorCode = "SENDER_ID_MISMATCH"
 )
+
+// Request of Instance ID API to manage topic subscriptions
+// https://developers.google.com/instance-id/reference/server#manage_relationship_maps_for_multiple_app_instances
+// easyjson:json
+type topicManagementRequest struct {
+	To     string   `json:"to"`
+	Tokens []string `json:"registration_tokens"`
+}
+
+// Results are in the order of the request tokens.
+// easyjson:json
+type topicManagementResponse struct {
+	Results []topicManagementResult `json:"results,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+}
+
+// easyjson:json
+type topicManagementResult struct {
+	Error string `json:"error,omitempty"`
+}
@@ -17,7 +17,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonC1cedd36DecodeGithubComHumansNetFcm(in *jlexer.Lexer, out *sendResponse) {
+func easyjsonC1cedd36DecodeGithubComHumansNetFcm(in *jlexer.Lexer, out *topicManagementResult) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -36,6 +36,289 @@ func easyjsonC1cedd36DecodeGithubComHumansNetFcm(in *jlexer.Lexer, out *sendResp
 			continue
 		}
 		switch key {
+		case "error":
+			out.Error = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC1cedd36EncodeGithubComHumansNetFcm(out *jwriter.Writer, in topicManagementResult) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Error))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v topicManagementResult) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v topicManagementResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *topicManagementResult) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *topicManagementResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm(l, v)
+}
+func easyjsonC1cedd36DecodeGithubComHumansNetFcm1(in *jlexer.Lexer, out *topicManagementResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "results":
+			if in.IsNull() {
+				in.Skip()
+				out.Results = nil
+			} else {
+				in.Delim('[')
+				if out.Results == nil {
+					if !in.IsDelim(']') {
+						out.Results = make([]topicManagementResult, 0, 4)
+					} else {
+						out.Results = []topicManagementResult{}
+					}
+				} else {
+					out.Results = (out.Results)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 topicManagementResult
+					(v1).UnmarshalEasyJSON(in)
+					out.Results = append(out.Results, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "error":
+			out.Error = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC1cedd36EncodeGithubComHumansNetFcm1(out *jwriter.Writer, in topicManagementResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Results) != 0 {
+		const prefix string = ",\"results\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v2, v3 := range in.Results {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Error))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v topicManagementResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v topicManagementResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *topicManagementResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *topicManagementResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm1(l, v)
+}
+func easyjsonC1cedd36DecodeGithubComHumansNetFcm2(in *jlexer.Lexer, out *topicManagementRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "to":
+			out.To = string(in.String())
+		case "registration_tokens":
+			if in.IsNull() {
+				in.Skip()
+				out.Tokens = nil
+			} else {
+				in.Delim('[')
+				if out.Tokens == nil {
+					if !in.IsDelim(']') {
+						out.Tokens = make([]string, 0, 4)
+					} else {
+						out.Tokens = []string{}
+					}
+				} else {
+					out.Tokens = (out.Tokens)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 string
+					v4 = string(in.String())
+					out.Tokens = append(out.Tokens, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC1cedd36EncodeGithubComHumansNetFcm2(out *jwriter.Writer, in topicManagementRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.To))
+	}
+	{
+		const prefix string = ",\"registration_tokens\":"
+		out.RawString(prefix)
+		if in.Tokens == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.Tokens {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v topicManagementRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v topicManagementRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *topicManagementRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *topicManagementRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm2(l, v)
+}
+func easyjsonC1cedd36DecodeGithubComHumansNetFcm3(in *jlexer.Lexer, out *sendResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
 		case "error":
 			if in.IsNull() {
 				in.Skip()
@@ -56,14 +339,24 @@ func easyjsonC1cedd36DecodeGithubComHumansNetFcm(in *jlexer.Lexer, out *sendResp
 		in.Consumed()
 	}
 }
-func easyjsonC1cedd36EncodeGithubComHumansNetFcm(out *jwriter.Writer, in sendResponse) {
+func easyjsonC1cedd36EncodeGithubComHumansNetFcm3(out *jwriter.Writer, in sendResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Error != nil {
-		const prefix string = ",\"error\":"
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
 		first = false
 		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.Error != nil {
+		const prefix string = ",\"error\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
 		(*in.Error).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
@@ -72,27 +365,27 @@ func easyjsonC1cedd36EncodeGithubComHumansNetFcm(out *jwriter.Writer, in sendRes
 // MarshalJSON supports json.Marshaler interface
 func (v sendResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC1cedd36EncodeGithubComHumansNetFcm(&w, v)
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v sendResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC1cedd36EncodeGithubComHumansNetFcm(w, v)
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *sendResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC1cedd36DecodeGithubComHumansNetFcm(&r, v)
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *sendResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC1cedd36DecodeGithubComHumansNetFcm(l, v)
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm3(l, v)
 }
-func easyjsonC1cedd36DecodeGithubComHumansNetFcm1(in *jlexer.Lexer, out *sendRequest) {
+func easyjsonC1cedd36DecodeGithubComHumansNetFcm4(in *jlexer.Lexer, out *sendRequest) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -111,6 +404,8 @@ func easyjsonC1cedd36DecodeGithubComHumansNetFcm1(in *jlexer.Lexer, out *sendReq
 			continue
 		}
 		switch key {
+		case "validate_only":
+			out.ValidateOnly = bool(in.Bool())
 		case "message":
 			if in.IsNull() {
 				in.Skip()
@@ -131,13 +426,24 @@ func easyjsonC1cedd36DecodeGithubComHumansNetFcm1(in *jlexer.Lexer, out *sendReq
 		in.Consumed()
 	}
 }
-func easyjsonC1cedd36EncodeGithubComHumansNetFcm1(out *jwriter.Writer, in sendRequest) {
+func easyjsonC1cedd36EncodeGithubComHumansNetFcm4(out *jwriter.Writer, in sendRequest) {
 	out.RawByte('{')
 	first := true
 	_ = first
+	if in.ValidateOnly {
+		const prefix string = ",\"validate_only\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.ValidateOnly))
+	}
 	{
 		const prefix string = ",\"message\":"
-		out.RawString(prefix[1:])
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
 		if in.Message == nil {
 			out.RawString("null")
 		} else {
@@ -150,27 +456,27 @@ func easyjsonC1cedd36EncodeGithubComHumansNetFcm1(out *jwriter.Writer, in sendRe
 // MarshalJSON supports json.Marshaler interface
 func (v sendRequest) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC1cedd36EncodeGithubComHumansNetFcm1(&w, v)
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v sendRequest) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC1cedd36EncodeGithubComHumansNetFcm1(w, v)
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *sendRequest) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC1cedd36DecodeGithubComHumansNetFcm1(&r, v)
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *sendRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC1cedd36DecodeGithubComHumansNetFcm1(l, v)
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm4(l, v)
 }
-func easyjsonC1cedd36DecodeGithubComHumansNetFcm2(in *jlexer.Lexer, out *responseError) {
+func easyjsonC1cedd36DecodeGithubComHumansNetFcm5(in *jlexer.Lexer, out *responseError) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -203,7 +509,7 @@ func easyjsonC1cedd36DecodeGithubComHumansNetFcm2(in *jlexer.Lexer, out *respons
 				in.Delim('[')
 				if out.Details == nil {
 					if !in.IsDelim(']') {
-						out.Details = make([]errorDetails, 0, 4)
+						out.Details = make([]errorDetails, 0, 1)
 					} else {
 						out.Details = []errorDetails{}
 					}
@@ -211,9 +517,9 @@ func easyjsonC1cedd36DecodeGithubComHumansNetFcm2(in *jlexer.Lexer, out *respons
 					out.Details = (out.Details)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v1 errorDetails
-					(v1).UnmarshalEasyJSON(in)
-					out.Details = append(out.Details, v1)
+					var v7 errorDetails
+					(v7).UnmarshalEasyJSON(in)
+					out.Details = append(out.Details, v7)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -228,7 +534,7 @@ func easyjsonC1cedd36DecodeGithubComHumansNetFcm2(in *jlexer.Lexer, out *respons
 		in.Consumed()
 	}
 }
-func easyjsonC1cedd36EncodeGithubComHumansNetFcm2(out *jwriter.Writer, in responseError) {
+func easyjsonC1cedd36EncodeGithubComHumansNetFcm5(out *jwriter.Writer, in responseError) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -268,11 +574,11 @@ func easyjsonC1cedd36EncodeGithubComHumansNetFcm2(out *jwriter.Writer, in respon
 		}
 		{
 			out.RawByte('[')
-			for v2, v3 := range in.Details {
-				if v2 > 0 {
+			for v8, v9 := range in.Details {
+				if v8 > 0 {
 					out.RawByte(',')
 				}
-				(v3).MarshalEasyJSON(out)
+				(v9).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -283,27 +589,106 @@ func easyjsonC1cedd36EncodeGithubComHumansNetFcm2(out *jwriter.Writer, in respon
 // MarshalJSON supports json.Marshaler interface
 func (v responseError) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC1cedd36EncodeGithubComHumansNetFcm2(&w, v)
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v responseError) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC1cedd36EncodeGithubComHumansNetFcm2(w, v)
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *responseError) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC1cedd36DecodeGithubComHumansNetFcm2(&r, v)
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *responseError) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC1cedd36DecodeGithubComHumansNetFcm2(l, v)
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm5(l, v)
 }
-func easyjsonC1cedd36DecodeGithubComHumansNetFcm3(in *jlexer.Lexer, out *errorDetails) {
+func easyjsonC1cedd36DecodeGithubComHumansNetFcm6(in *jlexer.Lexer, out *fieldViolation) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "field":
+			out.Field = string(in.String())
+		case "description":
+			out.Description = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC1cedd36EncodeGithubComHumansNetFcm6(out *jwriter.Writer, in fieldViolation) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Field != "" {
+		const prefix string = ",\"field\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Field))
+	}
+	if in.Description != "" {
+		const prefix string = ",\"description\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Description))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v fieldViolation) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v fieldViolation) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *fieldViolation) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *fieldViolation) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm6(l, v)
+}
+func easyjsonC1cedd36DecodeGithubComHumansNetFcm7(in *jlexer.Lexer, out *errorDetails) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -324,6 +709,29 @@ func easyjsonC1cedd36DecodeGithubComHumansNetFcm3(in *jlexer.Lexer, out *errorDe
 		switch key {
 		case "errorCode":
 			out.ErrorCode = errorCode(in.String())
+		case "fieldViolations":
+			if in.IsNull() {
+				in.Skip()
+				out.FieldViolations = nil
+			} else {
+				in.Delim('[')
+				if out.FieldViolations == nil {
+					if !in.IsDelim(']') {
+						out.FieldViolations = make([]fieldViolation, 0, 2)
+					} else {
+						out.FieldViolations = []fieldViolation{}
+					}
+				} else {
+					out.FieldViolations = (out.FieldViolations)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 fieldViolation
+					(v10).UnmarshalEasyJSON(in)
+					out.FieldViolations = append(out.FieldViolations, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -334,7 +742,7 @@ func easyjsonC1cedd36DecodeGithubComHumansNetFcm3(in *jlexer.Lexer, out *errorDe
 		in.Consumed()
 	}
 }
-func easyjsonC1cedd36EncodeGithubComHumansNetFcm3(out *jwriter.Writer, in errorDetails) {
+func easyjsonC1cedd36EncodeGithubComHumansNetFcm7(out *jwriter.Writer, in errorDetails) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -344,29 +752,48 @@ func easyjsonC1cedd36EncodeGithubComHumansNetFcm3(out *jwriter.Writer, in errorD
 		out.RawString(prefix[1:])
 		out.String(string(in.ErrorCode))
 	}
+	if len(in.FieldViolations) != 0 {
+		const prefix string = ",\"fieldViolations\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v11, v12 := range in.FieldViolations {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				(v12).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v errorDetails) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC1cedd36EncodeGithubComHumansNetFcm3(&w, v)
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm7(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v errorDetails) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC1cedd36EncodeGithubComHumansNetFcm3(w, v)
+	easyjsonC1cedd36EncodeGithubComHumansNetFcm7(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *errorDetails) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC1cedd36DecodeGithubComHumansNetFcm3(&r, v)
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm7(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *errorDetails) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC1cedd36DecodeGithubComHumansNetFcm3(l, v)
+	easyjsonC1cedd36DecodeGithubComHumansNetFcm7(l, v)
 }
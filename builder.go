@@ -0,0 +1,288 @@
+package fcm
+
+import (
+	"time"
+)
+
+// MessageBuilder constructs Message with a fluent API, e.g.
+//
+//	msg, err := NewMessage().
+//		ToToken(token).
+//		Title("Hello").
+//		Data("order_id", "42").
+//		Android(func(a *AndroidBuilder) {
+//			a.Priority(AndroidMessagePriorityHigh).TTL(time.Hour)
+//		}).
+//		Build()
+//
+// A builder could be used as a template: Clone returns an independent copy
+// to customize it per recipient.
+type MessageBuilder struct {
+	msg Message
+}
+
+// NewMessage returns builder of an empty message.
+func NewMessage() *MessageBuilder {
+	return &MessageBuilder{}
+}
+
+// ToToken targets the message to the device registration token, other targets are reset.
+func (b *MessageBuilder) ToToken(token string) *MessageBuilder {
+	b.msg.Token, b.msg.Topic, b.msg.Condition = token, "", ""
+	return b
+}
+
+// ToTopic targets the message to the topic, other targets are reset.
+func (b *MessageBuilder) ToTopic(topic string) *MessageBuilder {
+	b.msg.Token, b.msg.Topic, b.msg.Condition = "", topic, ""
+	return b
+}
+
+// ToCondition targets the message to the condition, other targets are reset.
+// See Condition to build the expression.
+func (b *MessageBuilder) ToCondition(cond string) *MessageBuilder {
+	b.msg.Token, b.msg.Topic, b.msg.Condition = "", "", cond
+	return b
+}
+
+// Title sets the notification title.
+func (b *MessageBuilder) Title(title string) *MessageBuilder {
+	b.notification().Title = title
+	return b
+}
+
+// Body sets the notification body.
+func (b *MessageBuilder) Body(body string) *MessageBuilder {
+	b.notification().Body = body
+	return b
+}
+
+// Image sets URL of the notification image.
+func (b *MessageBuilder) Image(url string) *MessageBuilder {
+	b.notification().Image = url
+	return b
+}
+
+// Data adds the key-value pair to the data payload.
+func (b *MessageBuilder) Data(key, value string) *MessageBuilder {
+	if b.msg.Data == nil {
+		b.msg.Data = make(map[string]string)
+	}
+
+	b.msg.Data[key] = value
+	return b
+}
+
+// Android configures Android specific options of the message.
+func (b *MessageBuilder) Android(fn func(a *AndroidBuilder)) *MessageBuilder {
+	if b.msg.Android == nil {
+		b.msg.Android = &AndroidConfig{}
+	}
+
+	fn(&AndroidBuilder{cfg: b.msg.Android})
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *MessageBuilder) Clone() *MessageBuilder {
+	return &MessageBuilder{msg: *b.msg.Clone()}
+}
+
+// Build validates and returns the message. The builder could be reused after
+// Build, changes made to it don't affect the returned message.
+func (b *MessageBuilder) Build() (*Message, error) {
+	msg := b.msg.Clone()
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+func (b *MessageBuilder) notification() *Notification {
+	if b.msg.Notification == nil {
+		b.msg.Notification = &Notification{}
+	}
+
+	return b.msg.Notification
+}
+
+// AndroidBuilder configures AndroidConfig of the message, see MessageBuilder.Android.
+type AndroidBuilder struct {
+	cfg *AndroidConfig
+}
+
+// CollapseKey sets identifier of a group of messages that can be collapsed.
+func (a *AndroidBuilder) CollapseKey(key string) *AndroidBuilder {
+	a.cfg.CollapseKey = key
+	return a
+}
+
+// Priority sets the message delivery priority.
+func (a *AndroidBuilder) Priority(priority AndroidMessagePriority) *AndroidBuilder {
+	a.cfg.Priority = priority
+	return a
+}
+
+// TTL sets how long the message is kept in FCM storage if the device is offline.
+func (a *AndroidBuilder) TTL(ttl time.Duration) *AndroidBuilder {
+	a.cfg.Ttl = NewDuration(ttl)
+	return a
+}
+
+// RestrictedPackageName sets package name of the application which receives the message.
+func (a *AndroidBuilder) RestrictedPackageName(name string) *AndroidBuilder {
+	a.cfg.RestrictedPackageName = name
+	return a
+}
+
+// Data adds the key-value pair to the Android data payload.
+func (a *AndroidBuilder) Data(key, value string) *AndroidBuilder {
+	if a.cfg.Data == nil {
+		a.cfg.Data = make(map[string]string)
+	}
+
+	a.cfg.Data[key] = value
+	return a
+}
+
+// DirectBootOk allows delivery of the message while the device is in direct boot mode.
+func (a *AndroidBuilder) DirectBootOk(ok bool) *AndroidBuilder {
+	a.cfg.DirectBootOk = ok
+	return a
+}
+
+// AnalyticsLabel sets label associated with the message's analytics data.
+func (a *AndroidBuilder) AnalyticsLabel(label string) *AndroidBuilder {
+	if a.cfg.FCMOptions == nil {
+		a.cfg.FCMOptions = &AndroidFCMOptions{}
+	}
+
+	a.cfg.FCMOptions.AnalyticsLabel = label
+	return a
+}
+
+// Notification configures Android notification of the message.
+func (a *AndroidBuilder) Notification(fn func(n *AndroidNotificationBuilder)) *AndroidBuilder {
+	if a.cfg.Notification == nil {
+		a.cfg.Notification = &AndroidNotification{}
+	}
+
+	fn(&AndroidNotificationBuilder{n: a.cfg.Notification})
+	return a
+}
+
+// AndroidNotificationBuilder configures AndroidNotification, see AndroidBuilder.Notification.
+type AndroidNotificationBuilder struct {
+	n *AndroidNotification
+}
+
+// Title sets the notification title.
+func (nb *AndroidNotificationBuilder) Title(title string) *AndroidNotificationBuilder {
+	nb.n.Title = title
+	return nb
+}
+
+// Body sets the notification body.
+func (nb *AndroidNotificationBuilder) Body(body string) *AndroidNotificationBuilder {
+	nb.n.Body = body
+	return nb
+}
+
+// TitleLoc sets key of the title string in the app's string resources and its format arguments.
+func (nb *AndroidNotificationBuilder) TitleLoc(key string, args ...string) *AndroidNotificationBuilder {
+	nb.n.TitleLocKey, nb.n.TitleLocArgs = key, args
+	return nb
+}
+
+// BodyLoc sets key of the body string in the app's string resources and its format arguments.
+func (nb *AndroidNotificationBuilder) BodyLoc(key string, args ...string) *AndroidNotificationBuilder {
+	nb.n.BodyLocKey, nb.n.BodyLocArgs = key, args
+	return nb
+}
+
+// Icon sets the notification icon.
+func (nb *AndroidNotificationBuilder) Icon(icon string) *AndroidNotificationBuilder {
+	nb.n.Icon = icon
+	return nb
+}
+
+// Color sets the notification icon color.
+func (nb *AndroidNotificationBuilder) Color(c Color) *AndroidNotificationBuilder {
+	nb.n.Color = c.Hex()
+	return nb
+}
+
+// Sound sets the sound to play when the device receives the notification.
+func (nb *AndroidNotificationBuilder) Sound(sound string) *AndroidNotificationBuilder {
+	nb.n.Sound = sound
+	return nb
+}
+
+// Tag sets identifier used to replace existing notifications in the notification drawer.
+func (nb *AndroidNotificationBuilder) Tag(tag string) *AndroidNotificationBuilder {
+	nb.n.Tag = tag
+	return nb
+}
+
+// ClickAction sets the action associated with a user click on the notification.
+func (nb *AndroidNotificationBuilder) ClickAction(action string) *AndroidNotificationBuilder {
+	nb.n.ClickAction = action
+	return nb
+}
+
+// ChannelID sets the notification channel ID.
+func (nb *AndroidNotificationBuilder) ChannelID(id string) *AndroidNotificationBuilder {
+	nb.n.ChannelId = id
+	return nb
+}
+
+// Image sets URL of the notification image.
+func (nb *AndroidNotificationBuilder) Image(url string) *AndroidNotificationBuilder {
+	nb.n.Image = url
+	return nb
+}
+
+// Priority sets the notification priority.
+func (nb *AndroidNotificationBuilder) Priority(priority NotificationPriority) *AndroidNotificationBuilder {
+	nb.n.NotificationPriority = priority
+	return nb
+}
+
+// Visibility sets the notification visibility.
+func (nb *AndroidNotificationBuilder) Visibility(visibility Visibility) *AndroidNotificationBuilder {
+	nb.n.Visibility = visibility
+	return nb
+}
+
+// NotificationCount sets the number of items the notification represents.
+func (nb *AndroidNotificationBuilder) NotificationCount(count int) *AndroidNotificationBuilder {
+	nb.n.NotificationCount = count
+	return nb
+}
+
+// Sticky keeps the notification when the user clicks it.
+func (nb *AndroidNotificationBuilder) Sticky(sticky bool) *AndroidNotificationBuilder {
+	nb.n.Sticky = sticky
+	return nb
+}
+
+// VibrateTimings sets the vibration pattern.
+func (nb *AndroidNotificationBuilder) VibrateTimings(timings ...time.Duration) *AndroidNotificationBuilder {
+	nb.n.VibrateTimings = make([]Duration, 0, len(timings))
+	for _, t := range timings {
+		nb.n.VibrateTimings = append(nb.n.VibrateTimings, Duration(t))
+	}
+
+	return nb
+}
+
+// LightSettings sets the LED color and blinking rate.
+func (nb *AndroidNotificationBuilder) LightSettings(c Color, on, off time.Duration) *AndroidNotificationBuilder {
+	nb.n.LightSettings = &LightSettings{
+		Color:            c,
+		LightOnDuration:  NewDuration(on),
+		LightOffDuration: NewDuration(off),
+	}
+	return nb
+}
@@ -0,0 +1,73 @@
+package fcm
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MessageBuilder", func() {
+	It("should build message", func() {
+		msg, err := NewMessage().
+			ToToken("token").
+			Title("title").
+			Body("body").
+			Data("key", "value").
+			Android(func(a *AndroidBuilder) {
+				a.Priority(AndroidMessagePriorityHigh).TTL(time.Hour).Notification(func(n *AndroidNotificationBuilder) {
+					n.ChannelID("news").Color(Color{Red: 1})
+				})
+			}).
+			Build()
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(msg).Should(Equal(&Message{
+			Token:        "token",
+			Data:         map[string]string{"key": "value"},
+			Notification: &Notification{Title: "title", Body: "body"},
+			Android: &AndroidConfig{
+				Priority: AndroidMessagePriorityHigh,
+				Ttl:      NewDuration(time.Hour),
+				Notification: &AndroidNotification{
+					ChannelId: "news",
+					Color:     "#ff0000",
+				},
+			},
+		}))
+	})
+
+	It("should reset previous target", func() {
+		msg, err := NewMessage().ToToken("token").ToTopic("news").Build()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(msg.Token).Should(BeEmpty())
+		Ω(msg.Topic).Should(Equal("news"))
+	})
+
+	It("should validate message", func() {
+		_, err := NewMessage().Data("from", "me").Build()
+		Ω(errors.Is(err, ErrInvalidTarget)).Should(BeTrue())
+		Ω(errors.Is(err, ErrReservedDataKey)).Should(BeTrue())
+	})
+
+	It("should customize cloned template independently", func() {
+		template := NewMessage().Title("title").Data("campaign", "spring").Android(func(a *AndroidBuilder) {
+			a.Data("key", "value")
+		})
+
+		first, err := template.Clone().ToToken("first").Data("name", "Alice").Build()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		second, err := template.Clone().ToToken("second").Title("other").Build()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(first.Data).Should(Equal(map[string]string{"campaign": "spring", "name": "Alice"}))
+		Ω(first.Notification.Title).Should(Equal("title"))
+		Ω(second.Data).Should(Equal(map[string]string{"campaign": "spring"}))
+		Ω(second.Notification.Title).Should(Equal("other"))
+
+		_, err = template.Build()
+		Ω(errors.Is(err, ErrInvalidTarget)).Should(BeTrue())
+	})
+})
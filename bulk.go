@@ -0,0 +1,293 @@
+package fcm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Error codes of BulkResult in addition to FCM error codes.
+const (
+	BulkCodeParseError     = "PARSE_ERROR"
+	BulkCodeInvalidMessage = "INVALID_MESSAGE"
+	BulkCodeUnknown        = "UNKNOWN"
+)
+
+// BulkResult is a JSON line of SendBulk results.
+type BulkResult struct {
+	// Line is the 1-based number of the input line.
+	Line      int    `json:"line"`
+	MessageID string `json:"message_id,omitempty"`
+	Code      string `json:"code,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkStats counts input lines processed by SendBulk.
+type BulkStats struct {
+	Sent    int
+	Failed  int
+	Skipped int
+}
+
+type bulkConfig struct {
+	concurrency int
+	rate        float64
+	skipLines   int
+	sendOpts    []SendOption
+}
+
+// BulkOption configures SendBulk with defined option.
+type BulkOption func(*bulkConfig)
+
+// WithBulkConcurrency returns BulkOption to configure number of concurrent sends.
+func WithBulkConcurrency(n int) BulkOption {
+	return func(cfg *bulkConfig) {
+		cfg.concurrency = n
+	}
+}
+
+// WithBulkRate returns BulkOption to limit number of sends per second, zero means no limit.
+func WithBulkRate(perSecond float64) BulkOption {
+	return func(cfg *bulkConfig) {
+		cfg.rate = perSecond
+	}
+}
+
+// WithBulkSkipLines returns BulkOption to skip the first n input lines,
+// e.g. processed before the restart, see LastBulkLine.
+func WithBulkSkipLines(n int) BulkOption {
+	return func(cfg *bulkConfig) {
+		cfg.skipLines = n
+	}
+}
+
+// WithBulkSendOptions returns BulkOption to configure options of every send, e.g. WithSendTimeout.
+func WithBulkSendOptions(opts ...SendOption) BulkOption {
+	return func(cfg *bulkConfig) {
+		cfg.sendOpts = append(cfg.sendOpts, opts...)
+	}
+}
+
+// messageSender is implemented by clients returning the name of the sent message, e.g. SimpleClient.
+type messageSender interface {
+	SendMessage(ctx context.Context, msg *Message, opts ...SendOption) (string, error)
+}
+
+// bulkItem is a parsed input line passed through SendStream.
+type bulkItem struct {
+	line      int
+	parseErr  error
+	messageID string
+}
+
+// bulkClient sends parsed lines recording message IDs to the items.
+type bulkClient struct {
+	client Client
+	opts   []SendOption
+	items  sync.Map // *Message -> *bulkItem
+}
+
+func (c *bulkClient) Send(ctx context.Context, msg *Message, opts ...SendOption) error {
+	v, _ := c.items.Load(msg)
+	item := v.(*bulkItem)
+	if item.parseErr != nil {
+		return item.parseErr
+	}
+
+	// the bulk options are copied as they are shared by concurrent sends
+	opts = append(c.opts[:len(c.opts):len(c.opts)], opts...)
+	if sender, ok := c.client.(messageSender); ok {
+		var err error
+		item.messageID, err = sender.SendMessage(ctx, msg, opts...)
+		return err
+	}
+
+	return c.client.Send(ctx, msg, opts...)
+}
+
+// SendBulk reads messages from JSON lines, either send requests {"message": {...}}
+// or messages themselves, sends them using the client and writes a BulkResult line
+// for every input line in the input order. Empty lines are skipped.
+//
+// Results are written in order, so after the interruption the sending is resumed
+// by skipping LastBulkLine lines of the input and appending to the results.
+// Lines interrupted by the context cancellation are not written to the results.
+func SendBulk(ctx context.Context, c Client, r io.Reader, w io.Writer, opts ...BulkOption) (BulkStats, error) {
+	cfg := bulkConfig{concurrency: 4}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	client := bulkClient{client: c, opts: cfg.sendOpts}
+	in := make(chan *Message)
+	out := SendStream(ctx, &client, in, WithStreamConcurrency(cfg.concurrency), WithStreamOrdered(true))
+
+	var stats BulkStats
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(in)
+		readErr <- readBulk(ctx, r, cfg, &client, in, &stats.Skipped)
+	}()
+
+	enc := json.NewEncoder(w)
+	var writeErr error
+	interrupted := false
+	for res := range out {
+		v, _ := client.items.Load(res.Message)
+		client.items.Delete(res.Message)
+		item := v.(*bulkItem)
+
+		// the line not sent because of the cancellation is left for the resume,
+		// the following lines are dropped to keep the results without gaps
+		if item.parseErr == nil && res.Err != nil && ctx.Err() != nil && errors.Is(res.Err, ctx.Err()) {
+			interrupted = true
+		}
+
+		if writeErr != nil || interrupted {
+			continue
+		}
+
+		result := BulkResult{Line: item.line, MessageID: item.messageID}
+		switch {
+		case item.parseErr != nil:
+			stats.Failed++
+			result.Code, result.Error = BulkCodeParseError, item.parseErr.Error()
+		case res.Err != nil:
+			stats.Failed++
+			result.Code, result.Error = bulkErrorCode(res.Err), res.Err.Error()
+		default:
+			stats.Sent++
+		}
+
+		if writeErr = enc.Encode(&result); writeErr != nil {
+			writeErr = fmt.Errorf("failed to write result: %w", writeErr)
+			cancel()
+		}
+	}
+
+	// the reader is done when the stream is closed
+	if err := <-readErr; err != nil && writeErr == nil {
+		writeErr = err
+	}
+
+	if writeErr != nil {
+		return stats, writeErr
+	}
+
+	return stats, ctx.Err()
+}
+
+func readBulk(ctx context.Context, r io.Reader, cfg bulkConfig, client *bulkClient, in chan<- *Message, skipped *int) error {
+	var limit <-chan time.Time
+	if cfg.rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.rate))
+		defer ticker.Stop()
+		limit = ticker.C
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if line <= cfg.skipLines || len(scanner.Bytes()) == 0 {
+			*skipped++
+			continue
+		}
+
+		msg, err := parseBulkLine(scanner.Bytes())
+		if err != nil {
+			msg = &Message{}
+		}
+
+		client.items.Store(msg, &bulkItem{line: line, parseErr: err})
+
+		if limit != nil && err == nil {
+			select {
+			case <-limit:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		select {
+		case in <- msg:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	return nil
+}
+
+func parseBulkLine(line []byte) (*Message, error) {
+	var req struct {
+		Message *json.RawMessage `json:"message"`
+	}
+
+	if err := json.Unmarshal(line, &req); err != nil {
+		return nil, fmt.Errorf("failed to parse line: %w", err)
+	}
+
+	data := line
+	if req.Message != nil {
+		data = *req.Message
+	}
+
+	var msg Message
+	if err := msg.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	return &msg, nil
+}
+
+func bulkErrorCode(err error) string {
+	var validationErrs ValidationErrors
+	switch {
+	case errors.Is(err, ErrUnregistered):
+		return string(errorCodeUnregistered)
+	case errors.Is(err, ErrSenderIDMismatch):
+		return string(errorCodeSenderIDMismatch)
+	case errors.Is(err, ErrInvalidToken):
+		return string(errorCodeInvalidArgument)
+	case errors.As(err, &validationErrs), errors.Is(err, ErrInvalidMessage):
+		return BulkCodeInvalidMessage
+	default:
+		return BulkCodeUnknown
+	}
+}
+
+// LastBulkLine returns the number of the last input line written to SendBulk results,
+// zero if there are no results.
+func LastBulkLine(results io.Reader) (int, error) {
+	last := 0
+	scanner := bufio.NewScanner(results)
+	for scanner.Scan() {
+		var res BulkResult
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			// the last line could be partially written on crash
+			continue
+		}
+
+		if res.Line > last {
+			last = res.Line
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read results: %w", err)
+	}
+
+	return last, nil
+}
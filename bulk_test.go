@@ -0,0 +1,162 @@
+package fcm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/oauth2"
+)
+
+var _ = Describe("SendBulk", func() {
+	const input = `{"message":{"token":"a"}}
+{"token":"stale"}
+
+not json
+{"topic":"news","notification":{"title":"hi"}}
+{"message":{}}
+`
+
+	var (
+		client   *ClientMock
+		cancelOn string
+		cancel   context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		cancelOn = ""
+		client = NewClientMock(GinkgoT())
+		client.SendMock.Set(func(ctx context.Context, msg *Message, _ ...SendOption) error {
+			if msg.Token != "" && msg.Token == cancelOn {
+				cancel()
+				<-ctx.Done()
+				return ctx.Err()
+			}
+
+			if err := msg.Validate(); err != nil {
+				return err
+			}
+
+			if msg.Token == "stale" {
+				return ErrUnregistered
+			}
+
+			return nil
+		})
+	})
+
+	It("should write result for every line in order", func() {
+		var out bytes.Buffer
+		stats, err := SendBulk(context.Background(), client, strings.NewReader(input), &out, WithBulkConcurrency(3))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(stats).Should(Equal(BulkStats{Sent: 2, Failed: 3, Skipped: 1}))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		Ω(lines).Should(HaveLen(5))
+		Ω(lines[0]).Should(MatchJSON(`{"line":1}`))
+		Ω(lines[1]).Should(MatchJSON(`{"line":2,"code":"UNREGISTERED","error":"Unregistered"}`))
+		Ω(lines[2]).Should(ContainSubstring(`{"line":4,"code":"PARSE_ERROR"`))
+		Ω(lines[3]).Should(MatchJSON(`{"line":5}`))
+		Ω(lines[4]).Should(ContainSubstring(`{"line":6,"code":"INVALID_MESSAGE"`))
+		Ω(client.SendAfterCounter()).Should(BeEquivalentTo(4))
+
+		last, err := LastBulkLine(&out)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(last).Should(Equal(6))
+	})
+
+	It("should resume after the last processed line", func() {
+		var out bytes.Buffer
+		stats, err := SendBulk(context.Background(), client, strings.NewReader(input), &out, WithBulkSkipLines(4))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(stats).Should(Equal(BulkStats{Sent: 1, Failed: 1, Skipped: 4}))
+		Ω(strings.Split(strings.TrimSpace(out.String()), "\n")[0]).Should(MatchJSON(`{"line":5}`))
+	})
+
+	It("should apply send options to every line", func() {
+		applied := make(chan SendOptions, 2)
+		client.SendMock.Set(func(_ context.Context, _ *Message, opts ...SendOption) error {
+			applied <- ApplySendOptions(opts...)
+			return nil
+		})
+
+		var out bytes.Buffer
+		input := "{\"token\":\"a\"}\n{\"token\":\"b\"}\n"
+		stats, err := SendBulk(context.Background(), client, strings.NewReader(input), &out,
+			WithBulkSendOptions(WithSendTimeout(time.Second), WithSendHeader("X-Bulk", "1")))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(stats).Should(Equal(BulkStats{Sent: 2}))
+
+		expected := SendOptions{Timeout: time.Second, Headers: map[string]string{"X-Bulk": "1"}}
+		Ω(<-applied).Should(Equal(expected))
+		Ω(<-applied).Should(Equal(expected))
+	})
+
+	It("should resume lines interrupted during the retry backoff", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		simple := newClient(
+			WithEndpoint(DefaultEndpoint),
+			WithHTTPClient(doerFunc(func(_ context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+				if strings.Contains(string(req.Body()), `"token":"b"`) {
+					cancel()
+					resp.SetStatusCode(fasthttp.StatusServiceUnavailable)
+				}
+
+				return nil
+			})))
+		simple.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"})
+
+		var out bytes.Buffer
+		input := "{\"token\":\"a\"}\n{\"token\":\"b\"}\n{\"token\":\"c\"}\n"
+		retry := RetryPolicy{MaxAttempts: 3, Backoff: func(int) time.Duration { return time.Minute }}
+		_, err := SendBulk(ctx, simple, strings.NewReader(input), &out,
+			WithBulkConcurrency(1), WithBulkSendOptions(WithSendRetry(retry)))
+		Ω(err).Should(MatchError(context.Canceled))
+		Ω(out.String()).Should(MatchJSON(`{"line":1}`))
+
+		last, err := LastBulkLine(&out)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(last).Should(Equal(1))
+	})
+
+	It("should resume lines interrupted by the cancellation", func() {
+		const input = `{"token":"a"}
+{"token":"b"}
+{"token":"c"}
+{"token":"d"}
+`
+
+		var out bytes.Buffer
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+
+		cancelOn = "b"
+		stats, err := SendBulk(ctx, client, strings.NewReader(input), &out, WithBulkConcurrency(1))
+		Ω(err).Should(MatchError(context.Canceled))
+		Ω(stats).Should(Equal(BulkStats{Sent: 1}))
+		Ω(out.String()).Should(MatchJSON(`{"line":1}`))
+
+		last, err := LastBulkLine(bytes.NewReader(out.Bytes()))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(last).Should(Equal(1))
+
+		cancelOn = ""
+		stats, err = SendBulk(context.Background(), client, strings.NewReader(input), &out, WithBulkSkipLines(last))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(stats).Should(Equal(BulkStats{Sent: 3, Skipped: 1}))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		Ω(lines).Should(HaveLen(4))
+		for i, line := range lines {
+			Ω(line).Should(MatchJSON(fmt.Sprintf(`{"line":%d}`, i+1)))
+		}
+	})
+})
@@ -0,0 +1,262 @@
+package fcm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+)
+
+// ErrUnmatchedRequest occurs if ReplayDoer has no recorded interaction for the request.
+var ErrUnmatchedRequest = errors.New("request is not recorded")
+
+const redacted = "REDACTED"
+
+var (
+	_ FastHTTPDoer = (*RecordingDoer)(nil)
+	_ FastHTTPDoer = (*ReplayDoer)(nil)
+
+	// defaultRedactedFields are JSON fields holding registration tokens and secrets.
+	defaultRedactedFields = []string{"token", "registration_tokens", "access_token", "private_key"}
+)
+
+// Interaction is a request and response pair recorded to the cassette.
+// Secrets are redacted, so the cassette can be committed.
+type Interaction struct {
+	Method          string            `json:"method"`
+	URI             string            `json:"uri"`
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	RequestBody     string            `json:"request_body,omitempty"`
+	StatusCode      int               `json:"status_code"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	ResponseBody    string            `json:"response_body,omitempty"`
+}
+
+// redactor hides secrets in headers and JSON bodies.
+type redactor struct {
+	fields map[string]bool
+}
+
+// CassetteOption configures RecordingDoer and ReplayDoer with defined option.
+type CassetteOption func(*redactor)
+
+// WithRedactedFields returns CassetteOption to redact values of the JSON fields
+// in addition to registration tokens and secrets.
+func WithRedactedFields(fields ...string) CassetteOption {
+	return func(r *redactor) {
+		for _, f := range fields {
+			r.fields[f] = true
+		}
+	}
+}
+
+func newRedactor(opts []CassetteOption) *redactor {
+	r := redactor{fields: make(map[string]bool)}
+	for _, f := range defaultRedactedFields {
+		r.fields[f] = true
+	}
+
+	for _, opt := range opts {
+		opt(&r)
+	}
+
+	return &r
+}
+
+// body returns the JSON body with redacted fields and sorted keys,
+// other bodies are returned as is.
+func (r *redactor) body(body []byte) string {
+	var v interface{}
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return string(body)
+	}
+
+	data, err := json.Marshal(r.value(v))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(data)
+}
+
+func (r *redactor) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range v {
+			if r.fields[k] {
+				v[k] = redactValue(fv)
+				continue
+			}
+
+			v[k] = r.value(fv)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = r.value(v[i])
+		}
+	}
+
+	return v
+}
+
+func redactValue(v interface{}) interface{} {
+	if list, ok := v.([]interface{}); ok {
+		for i := range list {
+			list[i] = redacted
+		}
+
+		return list
+	}
+
+	return redacted
+}
+
+func (r *redactor) requestHeaders(h *fasthttp.RequestHeader) map[string]string {
+	headers := make(map[string]string)
+	h.VisitAll(func(k, v []byte) {
+		switch string(k) {
+		case "Authorization":
+			headers[string(k)] = redacted
+		case "User-Agent", "Content-Length", "Host":
+		default:
+			headers[string(k)] = string(v)
+		}
+	})
+
+	return headers
+}
+
+// RecordingDoer performs requests using the next FastHTTPDoer and writes
+// the interactions to the cassette as JSON lines.
+type RecordingDoer struct {
+	next     FastHTTPDoer
+	redactor *redactor
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewRecordingDoer returns RecordingDoer writing the cassette to w.
+func NewRecordingDoer(next FastHTTPDoer, w io.Writer, opts ...CassetteOption) *RecordingDoer {
+	return &RecordingDoer{
+		next:     next,
+		redactor: newRedactor(opts),
+		w:        w,
+	}
+}
+
+func (d *RecordingDoer) Do(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	if err := d.next.Do(ctx, req, resp); err != nil {
+		return err
+	}
+
+	it := Interaction{
+		Method:          string(req.Header.Method()),
+		URI:             string(req.URI().RequestURI()),
+		RequestHeaders:  d.redactor.requestHeaders(&req.Header),
+		RequestBody:     d.redactor.body(req.Body()),
+		StatusCode:      resp.StatusCode(),
+		ResponseHeaders: map[string]string{"Content-Type": string(resp.Header.ContentType())},
+		ResponseBody:    d.redactor.body(resp.Body()),
+	}
+
+	line, err := json.Marshal(&it)
+	if err != nil {
+		return fmt.Errorf("failed to marshal interaction: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write interaction: %w", err)
+	}
+
+	return nil
+}
+
+// ReplayDoer serves responses recorded by RecordingDoer. The request matches
+// the interaction by method, URI and body with redacted secrets. Every interaction
+// is replayed once in the recorded order, unmatched requests fail with ErrUnmatchedRequest.
+type ReplayDoer struct {
+	redactor *redactor
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayDoer returns ReplayDoer reading the cassette from r.
+func NewReplayDoer(r io.Reader, opts ...CassetteOption) (*ReplayDoer, error) {
+	d := ReplayDoer{redactor: newRedactor(opts)}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var it Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &it); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette line %d: %w", lineNum, err)
+		}
+
+		d.interactions = append(d.interactions, it)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	d.used = make([]bool, len(d.interactions))
+	return &d, nil
+}
+
+func (d *ReplayDoer) Do(_ context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	method := string(req.Header.Method())
+	uri := string(req.URI().RequestURI())
+	body := d.redactor.body(req.Body())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, it := range d.interactions {
+		if d.used[i] || it.Method != method || it.URI != uri || it.RequestBody != body {
+			continue
+		}
+
+		d.used[i] = true
+		resp.Reset()
+		resp.SetStatusCode(it.StatusCode)
+		for k, v := range it.ResponseHeaders {
+			resp.Header.Set(k, v)
+		}
+
+		resp.SetBodyString(it.ResponseBody)
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s %s %s", ErrUnmatchedRequest, method, uri, body)
+}
+
+// Unused returns interactions which are not replayed yet.
+func (d *ReplayDoer) Unused() []Interaction {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var unused []Interaction
+	for i, it := range d.interactions {
+		if !d.used[i] {
+			unused = append(unused, it)
+		}
+	}
+
+	return unused
+}
@@ -0,0 +1,60 @@
+package fcm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/oauth2"
+)
+
+var _ = Describe("Cassette", func() {
+	newTestClient := func(doer FastHTTPDoer) *SimpleClient {
+		client := newClient(WithEndpoint(DefaultEndpoint), WithHTTPClient(doer))
+		client.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "secret"})
+		client.sendPath = []byte("/v1/projects/demo/messages:send")
+		return client
+	}
+
+	server := doerFunc(func(_ context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+		if bytes.Contains(req.Body(), []byte("stale-token")) {
+			resp.SetStatusCode(fasthttp.StatusNotFound)
+			resp.SetBodyString(`{"error":{"code":404,"errorDetails":[{"errorCode":"UNREGISTERED"}]}}`)
+			return nil
+		}
+
+		resp.Header.SetContentType("application/json")
+		resp.SetBodyString(`{"name":"projects/demo/messages/1"}`)
+		return nil
+	})
+
+	It("should record redacted traffic and replay it", func() {
+		var cassette bytes.Buffer
+		recording := newTestClient(NewRecordingDoer(server, &cassette))
+
+		name, err := recording.SendMessage(context.Background(), &Message{Token: "real-token"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(name).Should(Equal("projects/demo/messages/1"))
+		Ω(recording.Send(context.Background(), &Message{Token: "stale-token"})).Should(Equal(ErrUnregistered))
+
+		Ω(cassette.String()).ShouldNot(ContainSubstring("real-token"))
+		Ω(cassette.String()).ShouldNot(ContainSubstring("secret"))
+
+		replay, err := NewReplayDoer(bytes.NewReader(cassette.Bytes()))
+		Ω(err).ShouldNot(HaveOccurred())
+		replaying := newTestClient(replay)
+
+		// tokens are redacted, so the statuses are replayed in order
+		name, err = replaying.SendMessage(context.Background(), &Message{Token: "another-token"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(name).Should(Equal("projects/demo/messages/1"))
+		Ω(replaying.Send(context.Background(), &Message{Token: "another-token"})).Should(Equal(ErrUnregistered))
+		Ω(replay.Unused()).Should(BeEmpty())
+
+		err = replaying.Send(context.Background(), &Message{Topic: "news"})
+		Ω(errors.Is(err, ErrUnmatchedRequest)).Should(BeTrue())
+	})
+})
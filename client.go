@@ -2,7 +2,10 @@ package fcm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/valyala/fasthttp"
 	"golang.org/x/oauth2"
@@ -16,64 +19,166 @@ import (
 // to send messages.
 type Client interface {
 	// Send sends a message to the FCM server without retrying in case of service
-	// unavailability. A non-nil error is returned if a non-recoverable error
-	// occurs (i.e. if the sendResponse status code is not between 200 and 299).
-	Send(ctx context.Context, msg *Message) error
+	// unavailability unless retries are configured. A non-nil error is returned
+	// if a non-recoverable error occurs (i.e. if the sendResponse status code
+	// is not between 200 and 299). Options apply to this call only.
+	Send(ctx context.Context, msg *Message, opts ...SendOption) error
 }
 
 var _ Client = (*SimpleClient)(nil)
 
+// ErrClientClosed occurs on send after the client is closed.
+var ErrClientClosed = errors.New("client is closed")
+
 type SimpleClient struct {
 	client      FastHTTPDoer
+	ownsClient  bool
 	url         urlConfig
+	iidURL      urlConfig
 	tokenSource oauth2.TokenSource
-	sendPath    []byte
+	// iidTokenSource provides OAuth2 access tokens for Instance ID API,
+	// which doesn't accept self-signed JWTs used by FCM API.
+	iidTokenSource oauth2.TokenSource
+	sendPath       []byte
+
+	invalidTokenHandler func(token string, reason error)
+	tokenRegistry       TokenRegistry
+	retry               RetryPolicy
+	clock               Clock
+
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
 }
 
 // NewClient creates new Firebase Cloud Messaging SimpleClient based on API key and
-// with default endpoint and http client.
+// with default endpoint and http client. It panics on invalid credentials or options.
 func NewClient(serviceAccountJSONData []byte, opts ...Option) *SimpleClient {
+	c, err := NewClientE(serviceAccountJSONData, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// NewClientE is NewClient returning an error on invalid credentials or options.
+func NewClientE(serviceAccountJSONData []byte, opts ...Option) (*SimpleClient, error) {
 	defaultOpts := []Option{
 		WithEndpoint(DefaultEndpoint),
+		WithIIDEndpoint(DefaultIIDEndpoint),
 		WithCredentialsData(serviceAccountJSONData),
 		WithHTTPClient(DefaultHTTPAdapter),
 	}
 
 	opts = append(defaultOpts, opts...)
-	return newClient(opts...)
+	return tryNewClient(opts...)
 }
 
 func newClient(opts ...Option) *SimpleClient {
+	c, err := tryNewClient(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+func tryNewClient(opts ...Option) (*SimpleClient, error) {
 	c := SimpleClient{
-		tokenSource: &NoopTokenSource{},
+		tokenSource:    &NoopTokenSource{},
+		iidTokenSource: &NoopTokenSource{},
+		clock:          RealClock,
 	}
 
 	if err := applyOptions(&c, opts...); err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return &c
+	return &c, nil
 }
 
 // Send implementation of Client interface.
 // Docs for the reference: https://firebase.google.com/docs/reference/fcm/rest/v1/projects.messages/send
-func (c *SimpleClient) Send(ctx context.Context, msg *Message) error {
+func (c *SimpleClient) Send(ctx context.Context, msg *Message, opts ...SendOption) error {
+	_, err := c.send(ctx, msg, ApplySendOptions(opts...))
+	return err
+}
+
+// SendMessage sends a message like Send and returns its name assigned by FCM
+// in the "projects/*/messages/{message_id}" format.
+func (c *SimpleClient) SendMessage(ctx context.Context, msg *Message, opts ...SendOption) (string, error) {
+	return c.send(ctx, msg, ApplySendOptions(opts...))
+}
+
+// SendDryRun validates the message by FCM server without delivering it to the device.
+func (c *SimpleClient) SendDryRun(ctx context.Context, msg *Message, opts ...SendOption) error {
+	o := ApplySendOptions(opts...)
+	o.ValidateOnly = true
+
+	_, err := c.send(ctx, msg, o)
+	return err
+}
+
+func (c *SimpleClient) send(ctx context.Context, msg *Message, o SendOptions) (string, error) {
+	if !c.acquire() {
+		return "", ErrClientClosed
+	}
+
+	defer c.inflight.Done()
+
 	if err := msg.Validate(); err != nil {
-		return fmt.Errorf("invalid message: %w", err)
+		return "", fmt.Errorf("invalid message: %w", err)
 	}
 
 	sendReq := sendRequest{
-		Message: msg,
+		ValidateOnly: o.ValidateOnly,
+		Message:      msg,
 	}
 
 	body, err := sendReq.MarshalJSON()
 	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	retry := c.retry
+	if o.Retry != nil {
+		retry = *o.Retry
+	}
+
+	var name string
+	for attempt := 1; ; attempt++ {
+		var temporary bool
+		name, temporary, err = c.do(ctx, body, o.Headers)
+		if err == nil || !temporary || attempt >= retry.MaxAttempts {
+			break
+		}
+
+		// the interrupted send is reported by the context error, e.g. to be resent by SendBulk
+		if waitErr := sleepContext(ctx, retry.backoff(attempt)); waitErr != nil {
+			err = fmt.Errorf("%w, last error: %v", waitErr, err)
+			break
+		}
+	}
+
+	if !o.ValidateOnly {
+		c.trackToken(msg.Token, err)
+	}
+
+	return name, err
+}
+
+// do performs the send request, temporary is true if the request could be retried.
+func (c *SimpleClient) do(ctx context.Context, body []byte, headers map[string]string) (name string, temporary bool, err error) {
 	authHeaderValue, err := c.authHeaderValue()
 	if err != nil {
-		return err
+		return "", false, err
 	}
 
 	req := fasthttp.AcquireRequest()
@@ -87,21 +192,99 @@ func (c *SimpleClient) Send(ctx context.Context, msg *Message) error {
 	uri.SetSchemeBytes(c.url.Scheme)
 	uri.SetHostBytes(c.url.Host)
 	uri.SetPathBytes(c.sendPath)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
 	req.Header.SetBytesKV(contentTypeHeader, contentTypeHeaderV)
 	req.Header.SetBytesKV(authorizationHeader, authHeaderValue)
 	req.SetBody(body)
 
 	if err := c.client.Do(ctx, req, resp); err != nil {
-		return fmt.Errorf("failed to perform request: %w", err)
+		return "", true, fmt.Errorf("failed to perform request: %w", err)
+	}
+
+	statusCode := resp.StatusCode()
+	if err := handleResponse(statusCode, resp.Body()); err != nil {
+		return "", statusCode == fasthttp.StatusTooManyRequests || statusCode >= 500, err
+	}
+
+	var sendResp sendResponse
+	// the message is sent even if the name can't be parsed
+	_ = sendResp.UnmarshalJSON(resp.Body())
+	return sendResp.Name, false, nil
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.Backoff == nil {
+		return ExponentialBackoff(time.Second, time.Minute)(attempt)
+	}
+
+	return p.Backoff(attempt)
+}
+
+// sleepContext waits for the duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting new sends, which fail with ErrClientClosed, and waits
+// for the in-flight sends to finish or the context to be done.
+// Idle connections are closed only if the HTTP client is set by WithOwnedHTTPClient
+// and has CloseIdleConnections method, the shared DefaultHTTPAdapter is never closed.
+func (c *SimpleClient) Close(ctx context.Context) error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to wait for in-flight sends: %w", ctx.Err())
+	}
+
+	if closer, ok := c.client.(interface{ CloseIdleConnections() }); ok && c.ownsClient {
+		closer.CloseIdleConnections()
+	}
+
+	return nil
+}
+
+// acquire registers the in-flight send unless the client is closed.
+func (c *SimpleClient) acquire() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
 	}
 
-	return handleResponse(resp.StatusCode(), resp.Body())
+	c.inflight.Add(1)
+	return true
 }
 
 func (c *SimpleClient) authHeaderValue() ([]byte, error) {
+	return tokenHeaderValue(c.tokenSource)
+}
+
+func tokenHeaderValue(tokenSource oauth2.TokenSource) ([]byte, error) {
 	// TODO: consider to regenerate this value only when token is expired
 	//  e.g. cache and reuse if not expired
-	token, err := c.tokenSource.Token()
+	token, err := tokenSource.Token()
 	if err != nil {
 		return nil, fmt.Errorf("failed to grab oauth2 token: %w", err)
 	}
@@ -143,7 +326,27 @@ func handleResponse(statusCode int, respBody []byte) error {
 	switch errCode {
 	case errorCodeUnregistered:
 		return ErrUnregistered
+	case errorCodeSenderIDMismatch:
+		return ErrSenderIDMismatch
+	case errorCodeInvalidArgument:
+		if hasFieldViolation(resp.Error.Details, "message.token") {
+			return ErrInvalidToken
+		}
+
+		return fmt.Errorf("unsuccessful sendResponse with status code: %d: %s", statusCode, string(respBody))
 	default:
 		return fmt.Errorf("unsuccessful sendResponse with status code: %d: %s", statusCode, string(respBody))
 	}
 }
+
+func hasFieldViolation(details []errorDetails, field string) bool {
+	for _, detail := range details {
+		for _, v := range detail.FieldViolations {
+			if v.Field == field {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -15,8 +15,8 @@ import (
 type ClientMock struct {
 	t minimock.Tester
 
-	funcSend          func(ctx context.Context, msg *Message) (err error)
-	inspectFuncSend   func(ctx context.Context, msg *Message)
+	funcSend          func(ctx context.Context, msg *Message, opts ...SendOption) (err error)
+	inspectFuncSend   func(ctx context.Context, msg *Message, opts ...SendOption)
 	afterSendCounter  uint64
 	beforeSendCounter uint64
 	SendMock          mClientMockSend
@@ -54,8 +54,9 @@ type ClientMockSendExpectation struct {
 
 // ClientMockSendParams contains parameters of the Client.Send
 type ClientMockSendParams struct {
-	ctx context.Context
-	msg *Message
+	ctx  context.Context
+	msg  *Message
+	opts []SendOption
 }
 
 // ClientMockSendResults contains results of the Client.Send
@@ -64,7 +65,7 @@ type ClientMockSendResults struct {
 }
 
 // Expect sets up expected params for Client.Send
-func (mmSend *mClientMockSend) Expect(ctx context.Context, msg *Message) *mClientMockSend {
+func (mmSend *mClientMockSend) Expect(ctx context.Context, msg *Message, opts ...SendOption) *mClientMockSend {
 	if mmSend.mock.funcSend != nil {
 		mmSend.mock.t.Fatalf("ClientMock.Send mock is already set by Set")
 	}
@@ -73,7 +74,7 @@ func (mmSend *mClientMockSend) Expect(ctx context.Context, msg *Message) *mClien
 		mmSend.defaultExpectation = &ClientMockSendExpectation{}
 	}
 
-	mmSend.defaultExpectation.params = &ClientMockSendParams{ctx, msg}
+	mmSend.defaultExpectation.params = &ClientMockSendParams{ctx, msg, opts}
 	for _, e := range mmSend.expectations {
 		if minimock.Equal(e.params, mmSend.defaultExpectation.params) {
 			mmSend.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmSend.defaultExpectation.params)
@@ -84,7 +85,7 @@ func (mmSend *mClientMockSend) Expect(ctx context.Context, msg *Message) *mClien
 }
 
 // Inspect accepts an inspector function that has same arguments as the Client.Send
-func (mmSend *mClientMockSend) Inspect(f func(ctx context.Context, msg *Message)) *mClientMockSend {
+func (mmSend *mClientMockSend) Inspect(f func(ctx context.Context, msg *Message, opts ...SendOption)) *mClientMockSend {
 	if mmSend.mock.inspectFuncSend != nil {
 		mmSend.mock.t.Fatalf("Inspect function is already set for ClientMock.Send")
 	}
@@ -107,8 +108,8 @@ func (mmSend *mClientMockSend) Return(err error) *ClientMock {
 	return mmSend.mock
 }
 
-//Set uses given function f to mock the Client.Send method
-func (mmSend *mClientMockSend) Set(f func(ctx context.Context, msg *Message) (err error)) *ClientMock {
+// Set uses given function f to mock the Client.Send method
+func (mmSend *mClientMockSend) Set(f func(ctx context.Context, msg *Message, opts ...SendOption) (err error)) *ClientMock {
 	if mmSend.defaultExpectation != nil {
 		mmSend.mock.t.Fatalf("Default expectation is already set for the Client.Send method")
 	}
@@ -123,14 +124,14 @@ func (mmSend *mClientMockSend) Set(f func(ctx context.Context, msg *Message) (er
 
 // When sets expectation for the Client.Send which will trigger the result defined by the following
 // Then helper
-func (mmSend *mClientMockSend) When(ctx context.Context, msg *Message) *ClientMockSendExpectation {
+func (mmSend *mClientMockSend) When(ctx context.Context, msg *Message, opts ...SendOption) *ClientMockSendExpectation {
 	if mmSend.mock.funcSend != nil {
 		mmSend.mock.t.Fatalf("ClientMock.Send mock is already set by Set")
 	}
 
 	expectation := &ClientMockSendExpectation{
 		mock:   mmSend.mock,
-		params: &ClientMockSendParams{ctx, msg},
+		params: &ClientMockSendParams{ctx, msg, opts},
 	}
 	mmSend.expectations = append(mmSend.expectations, expectation)
 	return expectation
@@ -143,15 +144,15 @@ func (e *ClientMockSendExpectation) Then(err error) *ClientMock {
 }
 
 // Send implements Client
-func (mmSend *ClientMock) Send(ctx context.Context, msg *Message) (err error) {
+func (mmSend *ClientMock) Send(ctx context.Context, msg *Message, opts ...SendOption) (err error) {
 	mm_atomic.AddUint64(&mmSend.beforeSendCounter, 1)
 	defer mm_atomic.AddUint64(&mmSend.afterSendCounter, 1)
 
 	if mmSend.inspectFuncSend != nil {
-		mmSend.inspectFuncSend(ctx, msg)
+		mmSend.inspectFuncSend(ctx, msg, opts...)
 	}
 
-	mm_params := &ClientMockSendParams{ctx, msg}
+	mm_params := &ClientMockSendParams{ctx, msg, opts}
 
 	// Record call args
 	mmSend.SendMock.mutex.Lock()
@@ -168,7 +169,7 @@ func (mmSend *ClientMock) Send(ctx context.Context, msg *Message) (err error) {
 	if mmSend.SendMock.defaultExpectation != nil {
 		mm_atomic.AddUint64(&mmSend.SendMock.defaultExpectation.Counter, 1)
 		mm_want := mmSend.SendMock.defaultExpectation.params
-		mm_got := ClientMockSendParams{ctx, msg}
+		mm_got := ClientMockSendParams{ctx, msg, opts}
 		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
 			mmSend.t.Errorf("ClientMock.Send got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
@@ -180,9 +181,9 @@ func (mmSend *ClientMock) Send(ctx context.Context, msg *Message) (err error) {
 		return (*mm_results).err
 	}
 	if mmSend.funcSend != nil {
-		return mmSend.funcSend(ctx, msg)
+		return mmSend.funcSend(ctx, msg, opts...)
 	}
-	mmSend.t.Fatalf("Unexpected call to ClientMock.Send. %v %v", ctx, msg)
+	mmSend.t.Fatalf("Unexpected call to ClientMock.Send. %v %v %v", ctx, msg, opts)
 	return
 }
 
@@ -1,11 +1,16 @@
 package fcm
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	fuzz "github.com/google/gofuzz"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/oauth2"
 )
 
 var _ = Describe("Client", func() {
@@ -83,6 +88,48 @@ var _ = Describe("Client", func() {
 					})
 				})
 
+				When("errorDetails contain sender id mismatch error code", func() {
+					BeforeEach(func() {
+						resp.Error.Details = []errorDetails{
+							{
+								ErrorCode: errorCodeSenderIDMismatch,
+							},
+						}
+					})
+
+					It("should return sender id mismatch error", func() {
+						err := handleResponse(statusCode, respBody)
+						Ω(err).Should(Equal(ErrSenderIDMismatch))
+					})
+				})
+
+				When("errorDetails contain invalid argument error code", func() {
+					BeforeEach(func() {
+						resp.Error.Details = []errorDetails{
+							{
+								ErrorCode: errorCodeInvalidArgument,
+							},
+							{
+								FieldViolations: []fieldViolation{{Field: "message.token"}},
+							},
+						}
+					})
+
+					It("should return invalid token error for token violation", func() {
+						err := handleResponse(statusCode, respBody)
+						Ω(err).Should(Equal(ErrInvalidToken))
+					})
+
+					It("should return general error for other violations", func() {
+						resp.Error.Details[1].FieldViolations[0].Field = "message.android.ttl"
+						data, err := resp.MarshalJSON()
+						Ω(err).ShouldNot(HaveOccurred())
+
+						err = handleResponse(statusCode, data)
+						Ω(err).ShouldNot(Equal(ErrInvalidToken))
+					})
+				})
+
 				When("errorDetails contain unspecified error code", func() {
 					BeforeEach(func() {
 						resp.Error.Details = []errorDetails{
@@ -104,3 +151,68 @@ var _ = Describe("Client", func() {
 		})
 	})
 })
+
+var _ = Describe("SimpleClient Close", func() {
+	It("should wait for in-flight sends and reject new ones", func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		client := newClient(
+			WithEndpoint(DefaultEndpoint),
+			WithHTTPClient(doerFunc(func(context.Context, *fasthttp.Request, *fasthttp.Response) error {
+				close(started)
+				<-release
+				return nil
+			})))
+		client.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"})
+
+		sent := make(chan error, 1)
+		go func() {
+			sent <- client.Send(context.Background(), &Message{Token: "token"})
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		Ω(errors.Is(client.Close(ctx), context.DeadlineExceeded)).Should(BeTrue())
+		Ω(client.Send(context.Background(), &Message{Token: "token"})).Should(Equal(ErrClientClosed))
+
+		close(release)
+		Ω(client.Close(context.Background())).Should(Succeed())
+		Ω(sent).Should(Receive(BeNil()))
+	})
+})
+
+var _ = Describe("SimpleClient Close idle connections", func() {
+	send := func(opt Option) {
+		client := newClient(WithEndpoint(DefaultEndpoint), opt)
+		client.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"})
+
+		Ω(client.Send(context.Background(), &Message{Token: "token"})).Should(Succeed())
+		Ω(client.Close(context.Background())).Should(Succeed())
+	}
+
+	It("should close idle connections of the owned HTTP client", func() {
+		doer := &closingDoer{}
+		send(WithOwnedHTTPClient(doer))
+		Ω(doer.closed).Should(Equal(1))
+	})
+
+	It("should not close the shared HTTP client", func() {
+		doer := &closingDoer{}
+		send(WithHTTPClient(doer))
+		Ω(doer.closed).Should(Equal(0))
+	})
+})
+
+// closingDoer records calls of CloseIdleConnections.
+type closingDoer struct {
+	closed int
+}
+
+func (d *closingDoer) Do(context.Context, *fasthttp.Request, *fasthttp.Response) error {
+	return nil
+}
+
+func (d *closingDoer) CloseIdleConnections() {
+	d.closed++
+}
@@ -0,0 +1,183 @@
+package fcm
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers, so time dependent components
+// can be driven manually in tests.
+type Clock interface {
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+	// NewTimer returns Timer which sends the current time on its channel
+	// after the duration. Unlike After, the timer is released on Stop.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event timer like time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, it returns false if the timer
+	// has already fired or been stopped.
+	Stop() bool
+	// Reset changes the timer to fire after the duration, the timer must be
+	// stopped or fired with the channel drained.
+	Reset(d time.Duration) bool
+}
+
+var (
+	_ Clock = realClock{}
+	_ Clock = (*ManualClock)(nil)
+	_ Timer = realTimer{}
+	_ Timer = (*manualTimer)(nil)
+)
+
+// RealClock is Clock backed by the system time.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// ManualClock is Clock which time moves only when Advance or Set is called.
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+type manualTimer struct {
+	clock  *ManualClock
+	ch     chan time.Time
+	at     time.Time
+	active bool
+}
+
+// NewManualClock returns ManualClock set to the time.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *ManualClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &manualTimer{clock: c, ch: make(chan time.Time, 1)}
+	c.start(t, d)
+	return t
+}
+
+// Advance moves the clock forward by the duration firing due timers.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to the time firing due timers.
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+	timers := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(now) {
+			timers = append(timers, t)
+			continue
+		}
+
+		t.fire(now)
+	}
+
+	c.timers = timers
+}
+
+// start must be called with mu held.
+func (c *ManualClock) start(t *manualTimer, d time.Duration) {
+	if d <= 0 {
+		t.fire(c.now)
+		return
+	}
+
+	t.at = c.now.Add(d)
+	t.active = true
+	c.timers = append(c.timers, t)
+}
+
+// stop must be called with mu held.
+func (c *ManualClock) stop(t *manualTimer) bool {
+	if !t.active {
+		return false
+	}
+
+	t.active = false
+	for i, other := range c.timers {
+		if other == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.clock.stop(t)
+}
+
+func (t *manualTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.clock.stop(t)
+	t.clock.start(t, d)
+	return active
+}
+
+// fire sends the time unless the previous one is not received yet like time.Timer does.
+func (t *manualTimer) fire(now time.Time) {
+	t.active = false
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
@@ -0,0 +1,235 @@
+package fcm
+
+// Clone returns a deep copy of the message, it doesn't share maps,
+// slices and pointers with the original one, so the copy could be
+// modified concurrently with the original.
+func (msg *Message) Clone() *Message {
+	if msg == nil {
+		return nil
+	}
+
+	cp := *msg
+	cp.Data = copyStringMap(msg.Data)
+
+	if msg.Notification != nil {
+		n := *msg.Notification
+		cp.Notification = &n
+	}
+
+	if msg.Android != nil {
+		cp.Android = msg.Android.clone()
+	}
+
+	return &cp
+}
+
+// Merge applies fields set in the override to the message:
+//   - if the override has any target, it replaces all targets of the message;
+//   - non-empty strings, non-zero numbers and true bools replace the message values;
+//   - maps are merged key by key, override values win;
+//   - non-nil slices replace the message slices;
+//   - nested structs are merged recursively.
+//
+// The message doesn't share any memory with the override after the merge.
+func (msg *Message) Merge(override *Message) {
+	if override == nil {
+		return
+	}
+
+	if override.Token != "" || override.Topic != "" || override.Condition != "" {
+		msg.Token, msg.Topic, msg.Condition = override.Token, override.Topic, override.Condition
+	}
+
+	mergeString(&msg.Name, override.Name)
+	msg.Data = mergeStringMap(msg.Data, override.Data)
+
+	if override.Notification != nil {
+		if msg.Notification == nil {
+			msg.Notification = &Notification{}
+		}
+
+		mergeString(&msg.Notification.Title, override.Notification.Title)
+		mergeString(&msg.Notification.Body, override.Notification.Body)
+		mergeString(&msg.Notification.Image, override.Notification.Image)
+	}
+
+	if override.Android != nil {
+		if msg.Android == nil {
+			msg.Android = &AndroidConfig{}
+		}
+
+		msg.Android.merge(override.Android)
+	}
+}
+
+func (cfg *AndroidConfig) clone() *AndroidConfig {
+	cp := *cfg
+	cp.Data = copyStringMap(cfg.Data)
+
+	if cfg.Ttl != nil {
+		cp.Ttl = NewDuration(cfg.Ttl.Duration())
+	}
+
+	if cfg.FCMOptions != nil {
+		opts := *cfg.FCMOptions
+		cp.FCMOptions = &opts
+	}
+
+	if cfg.Notification != nil {
+		cp.Notification = cfg.Notification.clone()
+	}
+
+	return &cp
+}
+
+func (cfg *AndroidConfig) merge(override *AndroidConfig) {
+	mergeString(&cfg.CollapseKey, override.CollapseKey)
+	mergeString((*string)(&cfg.Priority), string(override.Priority))
+	mergeString(&cfg.RestrictedPackageName, override.RestrictedPackageName)
+	cfg.Data = mergeStringMap(cfg.Data, override.Data)
+	cfg.DirectBootOk = cfg.DirectBootOk || override.DirectBootOk
+
+	if override.Ttl != nil {
+		cfg.Ttl = NewDuration(override.Ttl.Duration())
+	}
+
+	if override.FCMOptions != nil {
+		if cfg.FCMOptions == nil {
+			cfg.FCMOptions = &AndroidFCMOptions{}
+		}
+
+		mergeString(&cfg.FCMOptions.AnalyticsLabel, override.FCMOptions.AnalyticsLabel)
+	}
+
+	if override.Notification != nil {
+		if cfg.Notification == nil {
+			cfg.Notification = &AndroidNotification{}
+		}
+
+		cfg.Notification.merge(override.Notification)
+	}
+}
+
+func (n *AndroidNotification) clone() *AndroidNotification {
+	cp := *n
+	cp.BodyLocArgs = copyStrings(n.BodyLocArgs)
+	cp.TitleLocArgs = copyStrings(n.TitleLocArgs)
+
+	if n.VibrateTimings != nil {
+		cp.VibrateTimings = append([]Duration(nil), n.VibrateTimings...)
+	}
+
+	if n.LightSettings != nil {
+		cp.LightSettings = n.LightSettings.clone()
+	}
+
+	return &cp
+}
+
+func (n *AndroidNotification) merge(override *AndroidNotification) {
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&n.Title, override.Title},
+		{&n.Body, override.Body},
+		{&n.Icon, override.Icon},
+		{&n.Sound, override.Sound},
+		{&n.Tag, override.Tag},
+		{&n.Color, override.Color},
+		{&n.ClickAction, override.ClickAction},
+		{&n.BodyLocKey, override.BodyLocKey},
+		{&n.TitleLocKey, override.TitleLocKey},
+		{&n.ChannelId, override.ChannelId},
+		{&n.Ticker, override.Ticker},
+		{&n.EventName, override.EventName},
+		{(*string)(&n.NotificationPriority), string(override.NotificationPriority)},
+		{(*string)(&n.Visibility), string(override.Visibility)},
+		{&n.Image, override.Image},
+	} {
+		mergeString(f.dst, f.src)
+	}
+
+	n.Sticky = n.Sticky || override.Sticky
+	n.LocalOnly = n.LocalOnly || override.LocalOnly
+	n.DefaultSound = n.DefaultSound || override.DefaultSound
+	n.DefaultVibrateTimings = n.DefaultVibrateTimings || override.DefaultVibrateTimings
+	n.DefaultLightSettings = n.DefaultLightSettings || override.DefaultLightSettings
+
+	if override.NotificationCount != 0 {
+		n.NotificationCount = override.NotificationCount
+	}
+
+	if override.BodyLocArgs != nil {
+		n.BodyLocArgs = copyStrings(override.BodyLocArgs)
+	}
+
+	if override.TitleLocArgs != nil {
+		n.TitleLocArgs = copyStrings(override.TitleLocArgs)
+	}
+
+	if override.VibrateTimings != nil {
+		n.VibrateTimings = append([]Duration(nil), override.VibrateTimings...)
+	}
+
+	if override.LightSettings != nil {
+		n.LightSettings = override.LightSettings.clone()
+	}
+}
+
+func (ls *LightSettings) clone() *LightSettings {
+	cp := *ls
+	if ls.LightOnDuration != nil {
+		cp.LightOnDuration = NewDuration(ls.LightOnDuration.Duration())
+	}
+
+	if ls.LightOffDuration != nil {
+		cp.LightOffDuration = NewDuration(ls.LightOffDuration.Duration())
+	}
+
+	return &cp
+}
+
+func mergeString(dst *string, src string) {
+	if src != "" {
+		*dst = src
+	}
+}
+
+// mergeStringMap returns dst with the src values, dst is allocated if nil.
+func mergeStringMap(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+
+	for k, v := range src {
+		dst[k] = v
+	}
+
+	return dst
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	cp := make(map[string]string, len(m))
+	for k, v := range m {
+		cp[k] = v
+	}
+
+	return cp
+}
+
+func copyStrings(ss []string) []string {
+	if ss == nil {
+		return nil
+	}
+
+	return append([]string(nil), ss...)
+}
@@ -0,0 +1,123 @@
+package fcm
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Message clone and merge", func() {
+	var template *Message
+
+	BeforeEach(func() {
+		template = &Message{
+			Topic:        "news",
+			Data:         map[string]string{"campaign": "spring"},
+			Notification: &Notification{Title: "title", Body: "body"},
+			Android: &AndroidConfig{
+				Ttl:  NewDuration(time.Hour),
+				Data: map[string]string{"key": "value"},
+				Notification: &AndroidNotification{
+					BodyLocArgs:    []string{"a"},
+					VibrateTimings: []Duration{Duration(time.Second)},
+					LightSettings:  &LightSettings{LightOnDuration: NewDuration(time.Second), LightOffDuration: NewDuration(time.Second)},
+				},
+			},
+		}
+	})
+
+	Context("Clone func", func() {
+		It("should copy fuzzed message", func() {
+			var msg Message
+			fuzz.New().NilChance(0).Fuzz(&msg)
+			Ω(msg.Clone()).Should(Equal(&msg))
+		})
+
+		It("should not share memory with the original", func() {
+			cp := template.Clone()
+			cp.Data["campaign"] = "autumn"
+			cp.Notification.Title = "other"
+			*cp.Android.Ttl = 0
+			cp.Android.Data["key"] = "other"
+			cp.Android.Notification.BodyLocArgs[0] = "b"
+			cp.Android.Notification.VibrateTimings[0] = 0
+			*cp.Android.Notification.LightSettings.LightOnDuration = 0
+
+			Ω(template.Data["campaign"]).Should(Equal("spring"))
+			Ω(template.Notification.Title).Should(Equal("title"))
+			Ω(template.Android.Ttl.Duration()).Should(Equal(time.Hour))
+			Ω(template.Android.Data["key"]).Should(Equal("value"))
+			Ω(template.Android.Notification.BodyLocArgs[0]).Should(Equal("a"))
+			Ω(template.Android.Notification.VibrateTimings[0]).Should(Equal(Duration(time.Second)))
+			Ω(template.Android.Notification.LightSettings.LightOnDuration).Should(Equal(NewDuration(time.Second)))
+		})
+
+		// Run with -race to detect shared memory.
+		It("should allow concurrent modification of clones", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					msg := template.Clone()
+					msg.Merge(&Message{
+						Token: strconv.Itoa(i),
+						Data:  map[string]string{"recipient": strconv.Itoa(i)},
+					})
+					msg.Android.Data["recipient"] = strconv.Itoa(i)
+					msg.Android.Notification.LightSettings.Color.Red = 1
+					Ω(msg.Validate()).Should(Succeed())
+				}(i)
+			}
+
+			wg.Wait()
+			Ω(template.Data).Should(HaveLen(1))
+		})
+	})
+
+	Context("Merge func", func() {
+		It("should override set fields and merge maps", func() {
+			override := &Message{
+				Token:        "token",
+				Data:         map[string]string{"name": "Alice"},
+				Notification: &Notification{Body: "Hi, Alice"},
+				Android: &AndroidConfig{
+					Priority: AndroidMessagePriorityHigh,
+					Notification: &AndroidNotification{
+						ChannelId:   "personal",
+						BodyLocArgs: []string{"Alice"},
+					},
+				},
+			}
+
+			template.Merge(override)
+
+			Ω(template.Token).Should(Equal("token"))
+			Ω(template.Topic).Should(BeEmpty())
+			Ω(template.Data).Should(Equal(map[string]string{"campaign": "spring", "name": "Alice"}))
+			Ω(template.Notification).Should(Equal(&Notification{Title: "title", Body: "Hi, Alice"}))
+			Ω(template.Android.Priority).Should(Equal(AndroidMessagePriorityHigh))
+			Ω(template.Android.Ttl.Duration()).Should(Equal(time.Hour))
+			Ω(template.Android.Notification.ChannelId).Should(Equal("personal"))
+			Ω(template.Android.Notification.BodyLocArgs).Should(Equal([]string{"Alice"}))
+
+			override.Data["name"] = "Bob"
+			override.Android.Notification.BodyLocArgs[0] = "Bob"
+			Ω(template.Data["name"]).Should(Equal("Alice"))
+			Ω(template.Android.Notification.BodyLocArgs[0]).Should(Equal("Alice"))
+		})
+
+		It("should allocate missing nested structs", func() {
+			msg := &Message{}
+			msg.Merge(template)
+			Ω(msg).Should(Equal(template))
+			Ω(msg.Android).ShouldNot(BeIdenticalTo(template.Android))
+		})
+	})
+})
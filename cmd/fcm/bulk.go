@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/humans-net/fcm"
+)
+
+func runBulk(args []string) error {
+	fs := flag.NewFlagSet("bulk", flag.ContinueOnError)
+	var cf clientFlags
+	cf.register(fs)
+	inPath := fs.String("in", "-", "JSONL `file` of messages or send requests, - to read from stdin")
+	outPath := fs.String("out", "", "JSONL `file` to write results to")
+	concurrency := fs.Int("concurrency", 8, "number of concurrent sends")
+	rate := fs.Float64("rate", 0, "maximum sends per second, 0 for no limit")
+	resume := fs.Bool("resume", false, "skip input lines already written to the results file and append to it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *outPath == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	skip := 0
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if *resume {
+		var err error
+		if skip, err = lastProcessedLine(*outPath); err != nil {
+			return err
+		}
+
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	var in io.Reader = os.Stdin
+	if *inPath != "-" {
+		f, err := os.Open(*inPath)
+		if err != nil {
+			return fmt.Errorf("failed to open input: %w", err)
+		}
+
+		defer f.Close()
+		in = f
+	}
+
+	out, err := os.OpenFile(*outPath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open results: %w", err)
+	}
+
+	defer out.Close()
+
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	// interrupted run writes results of in-flight sends to be resumed later
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	stats, err := fcm.SendBulk(ctx, client, in, out,
+		fcm.WithBulkConcurrency(*concurrency),
+		fcm.WithBulkRate(*rate),
+		fcm.WithBulkSkipLines(skip),
+		fcm.WithBulkSendOptions(fcm.WithSendTimeout(cf.timeout)))
+
+	fmt.Printf("sent: %d, failed: %d, skipped: %d\n", stats.Sent, stats.Failed, stats.Skipped)
+	return err
+}
+
+func lastProcessedLine(path string) (int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to open results: %w", err)
+	}
+
+	defer f.Close()
+	return fcm.LastBulkLine(f)
+}
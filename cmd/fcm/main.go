@@ -0,0 +1,133 @@
+// Command fcm sends push notifications and manages topic subscriptions
+// using Firebase Cloud Messaging HTTP v1 API.
+//
+// Usage:
+//
+//	fcm send -token <token> -title Hello -body World -data key=value
+//	fcm send -file message.json
+//	fcm validate -topic news -title Hello
+//	fcm subscribe -topic news <token>...
+//	fcm unsubscribe -topic news <token>...
+//	fcm bulk -in messages.jsonl -out results.jsonl -concurrency 16 -rate 100 -resume
+//
+// Service account credentials are read from the file passed with -credentials flag,
+// from FCM_CREDENTIALS environment variable containing JSON or from the file
+// pointed by GOOGLE_APPLICATION_CREDENTIALS environment variable.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/humans-net/fcm"
+)
+
+const usage = `Usage: fcm <command> [flags]
+
+Commands:
+  send         send the message
+  validate     validate the message by FCM without sending it
+  subscribe    subscribe tokens to the topic
+  unsubscribe  unsubscribe tokens from the topic
+  bulk         send messages from JSONL file writing JSONL results
+
+Run "fcm <command> -h" to list flags of the command.
+`
+
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "fcm:", err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "send":
+		return runSend(args, false)
+	case "validate":
+		return runSend(args, true)
+	case "subscribe":
+		return runTopic(args, true)
+	case "unsubscribe":
+		return runTopic(args, false)
+	case "bulk":
+		return runBulk(args)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+		return nil
+	default:
+		fmt.Fprintf(os.Stderr, "fcm: unknown command %q\n\n%s", cmd, usage)
+		return errUsage
+	}
+}
+
+// clientFlags are flags shared by all commands.
+type clientFlags struct {
+	credentials string
+	endpoint    string
+	iidEndpoint string
+	timeout     time.Duration
+}
+
+func (f *clientFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.credentials, "credentials", "", "service account JSON `file`")
+	fs.StringVar(&f.endpoint, "endpoint", fcm.DefaultEndpoint, "FCM endpoint `url`, e.g. of a local fake")
+	fs.StringVar(&f.iidEndpoint, "iid-endpoint", fcm.DefaultIIDEndpoint, "Instance ID endpoint `url`")
+	fs.DurationVar(&f.timeout, "timeout", 30*time.Second, "request timeout")
+}
+
+func (f *clientFlags) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), f.timeout)
+}
+
+func (f *clientFlags) client() (*fcm.SimpleClient, error) {
+	creds, err := f.readCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	return fcm.NewClientE(creds,
+		fcm.WithEndpoint(f.endpoint),
+		fcm.WithIIDEndpoint(f.iidEndpoint),
+		// credentials are applied again as the endpoint is used as audience of the token
+		fcm.WithCredentialsData(creds),
+	)
+}
+
+func (f *clientFlags) readCredentials() ([]byte, error) {
+	path := f.credentials
+	if path == "" {
+		if data := os.Getenv("FCM_CREDENTIALS"); data != "" {
+			return []byte(data), nil
+		}
+
+		path = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+
+	if path == "" {
+		return nil, errors.New("credentials are not set, use -credentials flag or FCM_CREDENTIALS environment variable")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/humans-net/fcm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestFcmCommand(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "fcm command")
+}
+
+var _ = Describe("run", func() {
+	It("should reject unknown command", func() {
+		Ω(run([]string{"unknown"})).Should(MatchError(errUsage))
+	})
+
+	It("should reject missing command", func() {
+		Ω(run(nil)).Should(MatchError(errUsage))
+	})
+})
+
+var _ = Describe("clientFlags", func() {
+	var (
+		fs *flag.FlagSet
+		cf clientFlags
+	)
+
+	BeforeEach(func() {
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		cf = clientFlags{}
+		cf.register(fs)
+	})
+
+	It("should use defaults", func() {
+		Ω(fs.Parse(nil)).Should(Succeed())
+		Ω(cf).Should(Equal(clientFlags{
+			endpoint:    fcm.DefaultEndpoint,
+			iidEndpoint: fcm.DefaultIIDEndpoint,
+			timeout:     30 * time.Second,
+		}))
+	})
+
+	It("should parse flags", func() {
+		Ω(fs.Parse([]string{
+			"-credentials", "creds.json",
+			"-endpoint", "http://localhost:8080",
+			"-iid-endpoint", "http://localhost:8081",
+			"-timeout", "5s",
+		})).Should(Succeed())
+
+		Ω(cf).Should(Equal(clientFlags{
+			credentials: "creds.json",
+			endpoint:    "http://localhost:8080",
+			iidEndpoint: "http://localhost:8081",
+			timeout:     5 * time.Second,
+		}))
+	})
+
+	It("should return error on invalid credentials", func() {
+		path := writeTempFile(`{"type":"service_account"}`)
+		Ω(fs.Parse([]string{"-credentials", path})).Should(Succeed())
+
+		client, err := cf.client()
+		Ω(err).Should(HaveOccurred())
+		Ω(client).Should(BeNil())
+	})
+
+	It("should return error on missing credentials file", func() {
+		Ω(fs.Parse([]string{"-credentials", filepath.Join(os.TempDir(), "fcm-missing.json")})).Should(Succeed())
+
+		_, err := cf.client()
+		Ω(err).Should(MatchError(ContainSubstring("failed to read credentials")))
+	})
+})
+
+var tempFiles []string
+
+var _ = AfterEach(func() {
+	for _, name := range tempFiles {
+		_ = os.Remove(name)
+	}
+
+	tempFiles = nil
+})
+
+// writeTempFile writes data to the temporary file removed after the test.
+func writeTempFile(data string) string {
+	f, err := ioutil.TempFile("", "fcm-test-*")
+	Ω(err).ShouldNot(HaveOccurred())
+	defer func() { _ = f.Close() }()
+
+	tempFiles = append(tempFiles, f.Name())
+	_, err = f.WriteString(data)
+	Ω(err).ShouldNot(HaveOccurred())
+	return f.Name()
+}
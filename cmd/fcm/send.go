@@ -0,0 +1,204 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/humans-net/fcm"
+)
+
+// keyValueFlag collects repeated key=value flags.
+type keyValueFlag map[string]string
+
+func (f keyValueFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f keyValueFlag) Set(s string) error {
+	idx := strings.IndexByte(s, '=')
+	if idx <= 0 {
+		return fmt.Errorf("%q is not key=value", s)
+	}
+
+	f[s[:idx]] = s[idx+1:]
+	return nil
+}
+
+// messageFlags describe the message, they override fields of the message file.
+type messageFlags struct {
+	file string
+
+	token, topic, condition string
+	title, body, image      string
+	data                    keyValueFlag
+
+	priority    string
+	ttl         time.Duration
+	collapseKey string
+	channelID   string
+	sound       string
+	icon        string
+	color       string
+	clickAction string
+	tag         string
+}
+
+func (f *messageFlags) register(fs *flag.FlagSet) {
+	f.data = make(keyValueFlag)
+
+	fs.StringVar(&f.file, "file", "", "JSON message `file`, - to read from stdin")
+	fs.StringVar(&f.token, "token", "", "registration token of the device")
+	fs.StringVar(&f.topic, "topic", "", "topic name")
+	fs.StringVar(&f.condition, "condition", "", "topics condition, e.g. \"'a' in topics && 'b' in topics\"")
+	fs.StringVar(&f.title, "title", "", "notification title")
+	fs.StringVar(&f.body, "body", "", "notification body")
+	fs.StringVar(&f.image, "image", "", "notification image `url`")
+	fs.Var(f.data, "data", "data `key=value`, can be repeated")
+	fs.StringVar(&f.priority, "priority", "", "android message priority: normal or high")
+	fs.DurationVar(&f.ttl, "ttl", 0, "android message time to live, e.g. 1h")
+	fs.StringVar(&f.collapseKey, "collapse-key", "", "android collapse key")
+	fs.StringVar(&f.channelID, "channel", "", "android notification channel id")
+	fs.StringVar(&f.sound, "sound", "", "android notification sound")
+	fs.StringVar(&f.icon, "icon", "", "android notification icon")
+	fs.StringVar(&f.color, "color", "", "android notification color in #rrggbb format")
+	fs.StringVar(&f.clickAction, "click-action", "", "android notification click action")
+	fs.StringVar(&f.tag, "tag", "", "android notification tag")
+}
+
+func (f *messageFlags) message() (*fcm.Message, error) {
+	msg, err := f.readFile()
+	if err != nil {
+		return nil, err
+	}
+
+	// the flags may describe only a part of the message,
+	// so the merged one is validated on send
+	override := &fcm.Message{
+		Token:     f.token,
+		Topic:     f.topic,
+		Condition: f.condition,
+	}
+
+	if len(f.data) > 0 {
+		override.Data = f.data
+	}
+
+	if f.title != "" || f.body != "" || f.image != "" {
+		override.Notification = &fcm.Notification{Title: f.title, Body: f.body, Image: f.image}
+	}
+
+	android := fcm.AndroidConfig{
+		Priority:    fcm.AndroidMessagePriority(strings.ToUpper(f.priority)),
+		CollapseKey: f.collapseKey,
+	}
+
+	if f.ttl != 0 {
+		android.Ttl = fcm.NewDuration(f.ttl)
+	}
+
+	notification := fcm.AndroidNotification{
+		ChannelId:   f.channelID,
+		Sound:       f.sound,
+		Icon:        f.icon,
+		ClickAction: f.clickAction,
+		Tag:         f.tag,
+	}
+
+	if f.color != "" {
+		c, err := fcm.ParseColor(f.color)
+		if err != nil {
+			return nil, err
+		}
+
+		notification.Color = c.Hex()
+	}
+
+	if f.channelID != "" || f.sound != "" || f.icon != "" || f.clickAction != "" || f.tag != "" || f.color != "" {
+		android.Notification = &notification
+	}
+
+	if android.Priority != "" || android.CollapseKey != "" || android.Ttl != nil || android.Notification != nil {
+		override.Android = &android
+	}
+
+	msg.Merge(override)
+	return msg, nil
+}
+
+func (f *messageFlags) readFile() (*fcm.Message, error) {
+	var msg fcm.Message
+	if f.file == "" {
+		return &msg, nil
+	}
+
+	var data []byte
+	var err error
+	if f.file == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(f.file)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	if err := msg.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	return &msg, nil
+}
+
+func runSend(args []string, dryRun bool) error {
+	name := "send"
+	if dryRun {
+		name = "validate"
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	var cf clientFlags
+	var mf messageFlags
+	cf.register(fs)
+	mf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	msg, err := mf.message()
+	if err != nil {
+		return err
+	}
+
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	if dryRun {
+		err = client.SendDryRun(ctx, msg)
+	} else {
+		err = client.Send(ctx, msg)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("ok")
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"time"
+
+	"github.com/humans-net/fcm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("keyValueFlag", func() {
+	It("should collect repeated pairs", func() {
+		f := make(keyValueFlag)
+		Ω(f.Set("b=2")).Should(Succeed())
+		Ω(f.Set("a=1=1")).Should(Succeed())
+		Ω(f.Set("c=")).Should(Succeed())
+
+		Ω(f).Should(Equal(keyValueFlag{"a": "1=1", "b": "2", "c": ""}))
+		Ω(f.String()).Should(Equal("a=1=1,b=2,c="))
+	})
+
+	It("should reject pair without key", func() {
+		f := make(keyValueFlag)
+		Ω(f.Set("value")).Should(HaveOccurred())
+		Ω(f.Set("=value")).Should(HaveOccurred())
+		Ω(f).Should(BeEmpty())
+	})
+})
+
+var _ = Describe("messageFlags", func() {
+	var (
+		fs *flag.FlagSet
+		mf messageFlags
+	)
+
+	BeforeEach(func() {
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		mf = messageFlags{}
+		mf.register(fs)
+	})
+
+	It("should build message from flags", func() {
+		Ω(fs.Parse([]string{
+			"-token", "token",
+			"-title", "Hello",
+			"-body", "World",
+			"-data", "a=1",
+			"-data", "b=2",
+			"-priority", "high",
+			"-ttl", "1h",
+			"-channel", "news",
+			"-color", "#ff0000",
+		})).Should(Succeed())
+
+		msg, err := mf.message()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(msg).Should(Equal(&fcm.Message{
+			Token:        "token",
+			Data:         map[string]string{"a": "1", "b": "2"},
+			Notification: &fcm.Notification{Title: "Hello", Body: "World"},
+			Android: &fcm.AndroidConfig{
+				Priority: fcm.AndroidMessagePriorityHigh,
+				Ttl:      fcm.NewDuration(time.Hour),
+				Notification: &fcm.AndroidNotification{
+					ChannelId: "news",
+					Color:     "#ff0000",
+				},
+			},
+		}))
+	})
+
+	It("should override message file with flags", func() {
+		path := writeTempFile(`{
+			"token": "file-token",
+			"data": {"a": "file", "c": "3"},
+			"notification": {"title": "File", "body": "Body"},
+			"android": {"collapse_key": "key", "notification": {"icon": "icon"}}
+		}`)
+
+		Ω(fs.Parse([]string{
+			"-file", path,
+			"-token", "token",
+			"-title", "Hello",
+			"-data", "a=1",
+			"-sound", "default",
+		})).Should(Succeed())
+
+		msg, err := mf.message()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(msg).Should(Equal(&fcm.Message{
+			Token:        "token",
+			Data:         map[string]string{"a": "1", "c": "3"},
+			Notification: &fcm.Notification{Title: "Hello", Body: "Body"},
+			Android: &fcm.AndroidConfig{
+				CollapseKey: "key",
+				Notification: &fcm.AndroidNotification{
+					Icon:  "icon",
+					Sound: "default",
+				},
+			},
+		}))
+	})
+
+	It("should keep message file without flags", func() {
+		path := writeTempFile(`{"topic": "news", "data": {"a": "1"}}`)
+		Ω(fs.Parse([]string{"-file", path})).Should(Succeed())
+
+		msg, err := mf.message()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(msg).Should(Equal(&fcm.Message{Topic: "news", Data: map[string]string{"a": "1"}}))
+	})
+
+	It("should reject invalid color", func() {
+		Ω(fs.Parse([]string{"-token", "token", "-color", "red"})).Should(Succeed())
+
+		_, err := mf.message()
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("should reject invalid message file", func() {
+		Ω(fs.Parse([]string{"-file", writeTempFile(`{`)})).Should(Succeed())
+
+		_, err := mf.message()
+		Ω(err).Should(MatchError(ContainSubstring("failed to parse message")))
+	})
+
+	It("should reject invalid data flag", func() {
+		Ω(fs.Parse([]string{"-data", "value"})).ShouldNot(Succeed())
+	})
+})
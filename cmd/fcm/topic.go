@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+func runTopic(args []string, subscribe bool) error {
+	name := "unsubscribe"
+	if subscribe {
+		name = "subscribe"
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: fcm %s -topic <topic> [flags] <token>...\n", name)
+		fs.PrintDefaults()
+	}
+
+	var cf clientFlags
+	cf.register(fs)
+	topic := fs.String("topic", "", "topic name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *topic == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	client, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	manage := client.UnsubscribeFromTopic
+	if subscribe {
+		manage = client.SubscribeToTopic
+	}
+
+	resp, err := manage(ctx, *topic, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	for _, e := range resp.Errors {
+		fmt.Printf("%s\t%s\n", fs.Arg(e.Index), e.Reason)
+	}
+
+	fmt.Printf("succeeded: %d, failed: %d\n", resp.SuccessCount, resp.FailureCount)
+	if resp.FailureCount > 0 {
+		return fmt.Errorf("failed to %s %d tokens", name, resp.FailureCount)
+	}
+
+	return nil
+}
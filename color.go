@@ -0,0 +1,84 @@
+package fcm
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+var (
+	_ color.Color = Color{}
+
+	hexColorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// ParseColor parses color in #rrggbb format used by AndroidNotification.Color.
+// The parsed color is opaque.
+func ParseColor(hex string) (Color, error) {
+	if !hexColorRegexp.MatchString(hex) {
+		return Color{}, fmt.Errorf("%w: %q, expected #rrggbb format", ErrInvalidColor, hex)
+	}
+
+	rgb, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return Color{
+		Red:   float32(rgb>>16&0xff) / 0xff,
+		Green: float32(rgb>>8&0xff) / 0xff,
+		Blue:  float32(rgb&0xff) / 0xff,
+	}, nil
+}
+
+// ColorFrom converts any color.Color, e.g. color.RGBA{R: 0xff, A: 0xff}, to Color.
+func ColorFrom(c color.Color) Color {
+	// color.Color returns alpha-premultiplied components
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		// zero alpha is sent as the opaque color, so the transparent one becomes black
+		return Color{}
+	}
+
+	res := Color{
+		Red:   float32(r) / float32(a),
+		Green: float32(g) / float32(a),
+		Blue:  float32(b) / float32(a),
+	}
+
+	if a != 0xffff {
+		res.Alpha = float32(a) / 0xffff
+	}
+
+	return res
+}
+
+// Hex formats the color in #rrggbb format, alpha is ignored.
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", colorComponent(c.Red, 0xff), colorComponent(c.Green, 0xff),
+		colorComponent(c.Blue, 0xff))
+}
+
+// RGBA implements color.Color interface, so Color could be used with image/color package.
+func (c Color) RGBA() (r, g, b, a uint32) {
+	// zero alpha is omitted in JSON, so it means the opaque color
+	a = 0xffff
+	if c.Alpha != 0 {
+		a = colorComponent(c.Alpha, 0xffff)
+	}
+
+	r = colorComponent(c.Red, 0xffff) * a / 0xffff
+	g = colorComponent(c.Green, 0xffff) * a / 0xffff
+	b = colorComponent(c.Blue, 0xffff) * a / 0xffff
+	return r, g, b, a
+}
+
+// colorComponent scales the component clamped to [0, 1] range to [0, max].
+func colorComponent(v float32, max uint32) uint32 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 1:
+		return max
+	default:
+		return uint32(math.Round(float64(v) * float64(max)))
+	}
+}
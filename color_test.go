@@ -0,0 +1,75 @@
+package fcm
+
+import (
+	"errors"
+	"image/color"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Color", func() {
+	It("should parse and format hex color", func() {
+		c, err := ParseColor("#FF8000")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(c.Red).Should(BeNumerically("==", 1))
+		Ω(c.Blue).Should(BeNumerically("==", 0))
+		Ω(c.Alpha).Should(BeNumerically("==", 0))
+		Ω(c.Hex()).Should(Equal("#ff8000"))
+	})
+
+	It("should fail on malformed hex color", func() {
+		_, err := ParseColor("#f80")
+		Ω(errors.Is(err, ErrInvalidColor)).Should(BeTrue())
+	})
+
+	It("should convert from and to image/color", func() {
+		c := ColorFrom(color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff})
+		Ω(c.Hex()).Should(Equal("#102030"))
+		Ω(color.RGBAModel.Convert(c)).Should(Equal(color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff}))
+	})
+
+	It("should convert transparency from image/color", func() {
+		Ω(ColorFrom(color.Black)).Should(Equal(Color{}))
+		Ω(ColorFrom(color.NRGBA{R: 0xff, A: 0x80}).Alpha).Should(BeNumerically("~", 0.5, 0.01))
+		Ω(color.RGBAModel.Convert(Color{Red: 1})).Should(Equal(color.RGBA{R: 0xff, A: 0xff}))
+	})
+
+	It("should marshal zero components", func() {
+		msg := Message{Android: &AndroidConfig{Notification: &AndroidNotification{
+			LightSettings: &LightSettings{Color: ColorFrom(color.Black)},
+		}}}
+
+		data, err := msg.MarshalJSON()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(data)).Should(ContainSubstring(`"color":{"red":0,"green":0,"blue":0}`))
+	})
+
+	It("should omit alpha of opaque color", func() {
+		msg := Message{Android: &AndroidConfig{Notification: &AndroidNotification{
+			Color:         ColorFrom(color.Black).Hex(),
+			LightSettings: &LightSettings{Color: Color{Red: 1}},
+		}}}
+
+		data, err := msg.MarshalJSON()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(data)).Should(MatchJSON(`{"android": {"notification": {
+			"color": "#000000",
+			"light_settings": {"color": {"red": 1, "green": 0, "blue": 0}}
+		}}}`))
+
+		var parsed Message
+		Ω(parsed.UnmarshalJSON(data)).Should(Succeed())
+		Ω(parsed).Should(Equal(msg))
+	})
+
+	It("should marshal alpha of translucent color", func() {
+		msg := Message{Android: &AndroidConfig{Notification: &AndroidNotification{
+			LightSettings: &LightSettings{Color: Color{Red: 1, Alpha: 0.5}},
+		}}}
+
+		data, err := msg.MarshalJSON()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(data)).Should(ContainSubstring(`"color":{"red":1,"green":0,"blue":0,"alpha":0.5}`))
+	})
+})
@@ -0,0 +1,445 @@
+package fcm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MaxConditionTopics is the maximum number of topics FCM accepts
+	// in a single condition expression.
+	MaxConditionTopics = 5
+
+	// topicsPrefix is an optional prefix of topic names, e.g. "/topics/news".
+	topicsPrefix = "/topics/"
+)
+
+var (
+	// ErrInvalidCondition occurs if message condition is not a valid FCM condition expression.
+	ErrInvalidCondition = errors.New("condition is invalid")
+
+	// topicNameRegexp matches topic names accepted by FCM.
+	// See https://firebase.google.com/docs/reference/fcm/rest/v1/projects.messages#resource:-message
+	topicNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9-_.~%]+$`)
+)
+
+// ConditionError describes a syntax or semantic error in a condition expression.
+// Pos is the byte offset in the expression where the error was detected.
+type ConditionError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ConditionError) Error() string {
+	return fmt.Sprintf("condition: position %d: %s", e.Pos, e.Msg)
+}
+
+// Unwrap allows to match any condition error with ErrInvalidCondition.
+func (e *ConditionError) Unwrap() error {
+	return ErrInvalidCondition
+}
+
+// ValidateCondition returns an error if the condition is not a valid FCM condition
+// expression, e.g. "'TopicA' in topics && ('TopicB' in topics || !('TopicC' in topics))".
+func ValidateCondition(cond string) error {
+	_, err := parseCondition(cond)
+	return err
+}
+
+// condNode is an element of the parsed condition expression tree.
+type condNode interface {
+	// writeTo renders the node, wrapping it into parentheses
+	// if its precedence is lower than the one of enclosing expression.
+	writeTo(sb *strings.Builder, prec int)
+	topicsCount() int
+	eval(topics topicSet) bool
+}
+
+type topicNode struct {
+	topic string
+}
+
+type notNode struct {
+	x condNode
+}
+
+type binaryNode struct {
+	op   condOp
+	x, y condNode
+}
+
+type condOp string
+
+const (
+	condOpAnd condOp = "&&"
+	condOpOr  condOp = "||"
+)
+
+// Operator precedence, higher binds tighter.
+const (
+	condPrecOr = iota + 1
+	condPrecAnd
+	condPrecUnary
+)
+
+func (op condOp) prec() int {
+	if op == condOpAnd {
+		return condPrecAnd
+	}
+
+	return condPrecOr
+}
+
+func (n topicNode) writeTo(sb *strings.Builder, _ int) {
+	sb.WriteByte('\'')
+	sb.WriteString(n.topic)
+	sb.WriteString("' in topics")
+}
+
+func (n notNode) writeTo(sb *strings.Builder, _ int) {
+	sb.WriteByte('!')
+	if _, ok := n.x.(notNode); ok {
+		n.x.writeTo(sb, condPrecUnary)
+		return
+	}
+
+	// Topic operand is wrapped as well, "!('a' in topics)" reads better than "!'a' in topics".
+	sb.WriteByte('(')
+	n.x.writeTo(sb, 0)
+	sb.WriteByte(')')
+}
+
+func (n binaryNode) writeTo(sb *strings.Builder, prec int) {
+	opPrec := n.op.prec()
+	if opPrec < prec {
+		sb.WriteByte('(')
+		defer sb.WriteByte(')')
+	}
+
+	// Operators are left associative, so the right operand of the same
+	// precedence is wrapped to keep the tree shape on the next parse.
+	n.x.writeTo(sb, opPrec)
+	sb.WriteString(" ")
+	sb.WriteString(string(n.op))
+	sb.WriteString(" ")
+	n.y.writeTo(sb, opPrec+1)
+}
+
+func (n topicNode) topicsCount() int {
+	return 1
+}
+
+func (n notNode) topicsCount() int {
+	return n.x.topicsCount()
+}
+
+func (n binaryNode) topicsCount() int {
+	return n.x.topicsCount() + n.y.topicsCount()
+}
+
+func (n topicNode) eval(topics topicSet) bool {
+	_, ok := topics[n.topic]
+	return ok
+}
+
+func (n notNode) eval(topics topicSet) bool {
+	return !n.x.eval(topics)
+}
+
+func (n binaryNode) eval(topics topicSet) bool {
+	if n.op == condOpAnd {
+		return n.x.eval(topics) && n.y.eval(topics)
+	}
+
+	return n.x.eval(topics) || n.y.eval(topics)
+}
+
+type condTokenKind int
+
+const (
+	condTokenEOF condTokenKind = iota
+	condTokenTopic
+	condTokenIn
+	condTokenTopics
+	condTokenNot
+	condTokenAnd
+	condTokenOr
+	condTokenLParen
+	condTokenRParen
+)
+
+func (k condTokenKind) String() string {
+	switch k {
+	case condTokenEOF:
+		return "end of condition"
+	case condTokenTopic:
+		return "quoted topic name"
+	case condTokenIn:
+		return "'in'"
+	case condTokenTopics:
+		return "'topics'"
+	case condTokenNot:
+		return "'!'"
+	case condTokenAnd:
+		return "'&&'"
+	case condTokenOr:
+		return "'||'"
+	case condTokenLParen:
+		return "'('"
+	case condTokenRParen:
+		return "')'"
+	default:
+		return "unknown token"
+	}
+}
+
+type condToken struct {
+	kind condTokenKind
+	pos  int
+	text string
+}
+
+// condParser is a recursive descent parser of the grammar:
+//
+//	expr    = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | primary
+//	primary = "(" expr ")" | topic "in" "topics"
+type condParser struct {
+	src    string
+	pos    int
+	tok    condToken
+	topics int
+}
+
+func parseCondition(cond string) (condNode, error) {
+	p := condParser{src: cond}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == condTokenEOF {
+		return nil, p.errorf(p.tok.pos, "empty condition")
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != condTokenEOF {
+		return nil, p.errorf(p.tok.pos, "unexpected %s", p.tok.kind)
+	}
+
+	return node, nil
+}
+
+func (p *condParser) parseOr() (condNode, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.kind == condTokenOr {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		x = binaryNode{op: condOpOr, x: x, y: y}
+	}
+
+	return x, nil
+}
+
+func (p *condParser) parseAnd() (condNode, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.kind == condTokenAnd {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		x = binaryNode{op: condOpAnd, x: x, y: y}
+	}
+
+	return x, nil
+}
+
+func (p *condParser) parseUnary() (condNode, error) {
+	if p.tok.kind != condTokenNot {
+		return p.parsePrimary()
+	}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	return notNode{x: x}, nil
+}
+
+func (p *condParser) parsePrimary() (condNode, error) {
+	switch p.tok.kind {
+	case condTokenLParen:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.tok.kind != condTokenRParen {
+			return nil, p.errorf(p.tok.pos, "expected %s, got %s", condTokenRParen, p.tok.kind)
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		return x, nil
+
+	case condTokenTopic:
+		topic := p.tok
+		if !topicNameRegexp.MatchString(topic.text) {
+			return nil, p.errorf(topic.pos, "invalid topic name %q", topic.text)
+		}
+
+		p.topics++
+		if p.topics > MaxConditionTopics {
+			return nil, p.errorf(topic.pos, "too many topics, at most %d are allowed", MaxConditionTopics)
+		}
+
+		if err := p.expect(condTokenIn); err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(condTokenTopics); err != nil {
+			return nil, err
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		return topicNode{topic: topic.text}, nil
+
+	default:
+		return nil, p.errorf(p.tok.pos, "expected %s, %s or %s, got %s",
+			condTokenTopic, condTokenNot, condTokenLParen, p.tok.kind)
+	}
+}
+
+// expect advances to the next token and checks it has the given kind.
+func (p *condParser) expect(kind condTokenKind) error {
+	if err := p.next(); err != nil {
+		return err
+	}
+
+	if p.tok.kind != kind {
+		return p.errorf(p.tok.pos, "expected %s, got %s", kind, p.tok.kind)
+	}
+
+	return nil
+}
+
+// next scans the next token from the source into p.tok.
+func (p *condParser) next() error {
+	for p.pos < len(p.src) && isConditionSpace(p.src[p.pos]) {
+		p.pos++
+	}
+
+	start := p.pos
+	if start >= len(p.src) {
+		p.tok = condToken{kind: condTokenEOF, pos: start}
+		return nil
+	}
+
+	switch c := p.src[start]; c {
+	case '(':
+		p.tok = condToken{kind: condTokenLParen, pos: start}
+		p.pos++
+	case ')':
+		p.tok = condToken{kind: condTokenRParen, pos: start}
+		p.pos++
+	case '!':
+		p.tok = condToken{kind: condTokenNot, pos: start}
+		p.pos++
+	case '&', '|':
+		if start+1 >= len(p.src) || p.src[start+1] != c {
+			return p.errorf(start, "unexpected %q, expected %q", c, string([]byte{c, c}))
+		}
+
+		kind := condTokenAnd
+		if c == '|' {
+			kind = condTokenOr
+		}
+
+		p.tok = condToken{kind: kind, pos: start}
+		p.pos += 2
+	case '\'', '"':
+		end := start + 1
+		for end < len(p.src) && p.src[end] != c {
+			end++
+		}
+
+		if end >= len(p.src) {
+			return p.errorf(start, "unterminated topic name")
+		}
+
+		p.tok = condToken{kind: condTokenTopic, pos: start, text: p.src[start+1 : end]}
+		p.pos = end + 1
+	default:
+		end := start
+		for end < len(p.src) && isConditionLetter(p.src[end]) {
+			end++
+		}
+
+		switch word := p.src[start:end]; word {
+		case "in":
+			p.tok = condToken{kind: condTokenIn, pos: start}
+		case "topics":
+			p.tok = condToken{kind: condTokenTopics, pos: start}
+		case "":
+			return p.errorf(start, "unexpected character %q", c)
+		default:
+			return p.errorf(start, "unexpected word %q", word)
+		}
+
+		p.pos = end
+	}
+
+	return nil
+}
+
+func (p *condParser) errorf(pos int, format string, args ...interface{}) error {
+	return &ConditionError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isConditionSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isConditionLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,130 @@
+package fcm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Condition is a typed FCM condition expression which renders into
+// the Message.Condition string, e.g.
+//
+//	cond := InTopic("news").And(InTopic("sports").Or(NotCondition(InTopic("cats"))))
+//	msg.Condition, err = cond.Build()
+//
+// Errors like an invalid topic name or too many topics are detected while
+// the expression is built and returned by Build. A nil operand is reported
+// the same way with ErrInvalidCondition.
+type Condition struct {
+	node condNode
+	err  error
+}
+
+// InTopic returns condition which matches devices subscribed to the topic.
+func InTopic(topic string) *Condition {
+	if !topicNameRegexp.MatchString(topic) {
+		return &Condition{err: fmt.Errorf("%w: invalid topic name %q", ErrInvalidCondition, topic)}
+	}
+
+	return &Condition{node: topicNode{topic: topic}}
+}
+
+// ParseCondition parses condition expression, so it could be inspected or
+// extended with the builder methods.
+func ParseCondition(cond string) (*Condition, error) {
+	node, err := parseCondition(cond)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Condition{node: node}, nil
+}
+
+// And returns condition which matches devices matched by c and all of others.
+func (c *Condition) And(others ...*Condition) *Condition {
+	return c.combine(condOpAnd, others)
+}
+
+// Or returns condition which matches devices matched by c or any of others.
+func (c *Condition) Or(others ...*Condition) *Condition {
+	return c.combine(condOpOr, others)
+}
+
+// NotCondition returns condition which matches devices not matched by c,
+// the same as c.Not().
+func NotCondition(c *Condition) *Condition {
+	return c.Not()
+}
+
+// Not returns condition which matches devices not matched by c.
+func (c *Condition) Not() *Condition {
+	if c == nil {
+		return nilCondition()
+	}
+
+	if c.err != nil {
+		return c
+	}
+
+	return &Condition{node: notNode{x: c.node}}
+}
+
+func (c *Condition) combine(op condOp, others []*Condition) *Condition {
+	if c == nil {
+		return nilCondition()
+	}
+
+	if c.err != nil {
+		return c
+	}
+
+	node := c.node
+	for _, other := range others {
+		if other == nil {
+			return nilCondition()
+		}
+
+		if other.err != nil {
+			return other
+		}
+
+		node = binaryNode{op: op, x: node, y: other.node}
+	}
+
+	if cnt := node.topicsCount(); cnt > MaxConditionTopics {
+		return &Condition{err: fmt.Errorf("%w: %d topics used, at most %d are allowed",
+			ErrInvalidCondition, cnt, MaxConditionTopics)}
+	}
+
+	return &Condition{node: node}
+}
+
+func nilCondition() *Condition {
+	return &Condition{err: fmt.Errorf("%w: nil condition", ErrInvalidCondition)}
+}
+
+// Build returns the condition expression string or an error
+// occurred while the condition was built.
+func (c *Condition) Build() (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+
+	return c.String(), nil
+}
+
+// String renders the condition expression, it returns an empty string
+// if the condition is invalid.
+func (c *Condition) String() string {
+	if c.err != nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	c.node.writeTo(&sb, 0)
+	return sb.String()
+}
+
+// Err returns an error occurred while the condition was built.
+func (c *Condition) Err() error {
+	return c.err
+}
@@ -0,0 +1,114 @@
+package fcm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// topicSet is a set of topics a device is subscribed to.
+type topicSet map[string]struct{}
+
+func newTopicSet(topics []string) topicSet {
+	set := make(topicSet, len(topics))
+	for _, topic := range topics {
+		set[strings.TrimPrefix(topic, topicsPrefix)] = struct{}{}
+	}
+
+	return set
+}
+
+// EvaluateCondition reports whether a device subscribed to the topics
+// would receive a message sent with the condition.
+// Topics could be passed with or without "/topics/" prefix.
+func EvaluateCondition(cond string, topics []string) (bool, error) {
+	c, err := ParseCondition(cond)
+	if err != nil {
+		return false, err
+	}
+
+	return c.Evaluate(topics), nil
+}
+
+// Evaluate reports whether a device subscribed to the topics matches the condition.
+// Invalid condition matches nothing.
+func (c *Condition) Evaluate(topics []string) bool {
+	if c.err != nil {
+		return false
+	}
+
+	return c.node.eval(newTopicSet(topics))
+}
+
+// Subscription is a device registration token with topics it is subscribed to.
+type Subscription struct {
+	Token  string
+	Topics []string
+}
+
+// SubscriptionIterator iterates over known device subscriptions,
+// e.g. rows of a database query. The usage is similar to sql.Rows:
+//
+//	for it.Next() {
+//		sub := it.Subscription()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type SubscriptionIterator interface {
+	// Next advances the iterator, it returns false when there are no more
+	// subscriptions or an error occurred.
+	Next() bool
+	// Subscription returns the current subscription.
+	Subscription() Subscription
+	// Err returns an error occurred during the iteration.
+	Err() error
+}
+
+// EvaluateConditionBulk returns tokens of the subscriptions matching the condition.
+// The condition is parsed once for the whole iteration.
+func EvaluateConditionBulk(cond string, it SubscriptionIterator) ([]string, error) {
+	c, err := ParseCondition(cond)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []string
+	for it.Next() {
+		sub := it.Subscription()
+		if c.Evaluate(sub.Topics) {
+			tokens = append(tokens, sub.Token)
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate subscriptions: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// NewSubscriptionIterator returns SubscriptionIterator over the slice.
+func NewSubscriptionIterator(subs []Subscription) SubscriptionIterator {
+	return &sliceSubscriptionIterator{subs: subs, idx: -1}
+}
+
+type sliceSubscriptionIterator struct {
+	subs []Subscription
+	idx  int
+}
+
+func (it *sliceSubscriptionIterator) Next() bool {
+	if it.idx+1 >= len(it.subs) {
+		return false
+	}
+
+	it.idx++
+	return true
+}
+
+func (it *sliceSubscriptionIterator) Subscription() Subscription {
+	return it.subs[it.idx]
+}
+
+func (it *sliceSubscriptionIterator) Err() error {
+	return nil
+}
@@ -0,0 +1,135 @@
+package fcm
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Condition", func() {
+	Context("ValidateCondition func", func() {
+		table.DescribeTable("valid conditions",
+			func(cond string) {
+				Ω(ValidateCondition(cond)).Should(Succeed())
+			},
+			table.Entry("single topic", "'TopicA' in topics"),
+			table.Entry("double quotes", `"TopicA" in topics`),
+			table.Entry("and", "'TopicA' in topics && 'TopicB' in topics"),
+			table.Entry("or with parentheses", "'TopicA' in topics && ('TopicB' in topics || 'TopicC' in topics)"),
+			table.Entry("negation", "!('TopicA' in topics) && !'TopicB' in topics"),
+			table.Entry("five topics", "'a' in topics || 'b' in topics || 'c' in topics || 'd' in topics || 'e' in topics"),
+			table.Entry("extra spaces", "  ( 'a'  in topics )\t"),
+		)
+
+		table.DescribeTable("invalid conditions",
+			func(cond string, pos int) {
+				err := ValidateCondition(cond)
+				Ω(errors.Is(err, ErrInvalidCondition)).Should(BeTrue())
+
+				var condErr *ConditionError
+				Ω(errors.As(err, &condErr)).Should(BeTrue())
+				Ω(condErr.Pos).Should(Equal(pos))
+			},
+			table.Entry("empty", "", 0),
+			table.Entry("missing in", "'a' topics", 4),
+			table.Entry("single ampersand", "'a' in topics & 'b' in topics", 14),
+			table.Entry("dangling operator", "'a' in topics &&", 16),
+			table.Entry("unbalanced parentheses", "('a' in topics", 14),
+			table.Entry("extra closing parenthesis", "'a' in topics)", 13),
+			table.Entry("unterminated topic", "'a in topics", 0),
+			table.Entry("invalid topic name", "'a b' in topics", 0),
+			table.Entry("unknown word", "'a' in topic", 7),
+			table.Entry("six topics", "'a' in topics || 'b' in topics || 'c' in topics || 'd' in topics || 'e' in topics || 'f' in topics", 85),
+		)
+	})
+
+	Context("Message.Validate func", func() {
+		It("should reject message with malformed condition", func() {
+			msg := &Message{Condition: "'a' in topics &&& 'b' in topics"}
+			Ω(errors.Is(msg.Validate(), ErrInvalidCondition)).Should(BeTrue())
+		})
+	})
+
+	Context("Condition builder", func() {
+		It("should render expression with minimal parentheses", func() {
+			cond, err := InTopic("news").And(InTopic("sports").Or(NotCondition(InTopic("cats")))).Build()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cond).Should(Equal("'news' in topics && ('sports' in topics || !('cats' in topics))"))
+		})
+
+		table.DescribeTable("round trip with the parser",
+			func(c *Condition) {
+				cond, err := c.Build()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				parsed, err := ParseCondition(cond)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(parsed).Should(Equal(c))
+				Ω(parsed.String()).Should(Equal(cond))
+			},
+			table.Entry("single topic", InTopic("a")),
+			table.Entry("left nested", InTopic("a").And(InTopic("b"), InTopic("c"))),
+			table.Entry("right nested", InTopic("a").And(InTopic("b").And(InTopic("c")))),
+			table.Entry("mixed operators", InTopic("a").Or(InTopic("b")).And(InTopic("c").Or(InTopic("d")))),
+			table.Entry("double negation", InTopic("a").Or(InTopic("b")).Not().Not()),
+			table.Entry("negation function", InTopic("a").And(NotCondition(InTopic("b").Or(InTopic("c"))))),
+		)
+
+		It("should fail on invalid topic name", func() {
+			_, err := InTopic("a").Or(InTopic("b c")).Build()
+			Ω(errors.Is(err, ErrInvalidCondition)).Should(BeTrue())
+		})
+
+		It("should fail on too many topics", func() {
+			c := InTopic("a").Or(InTopic("b"), InTopic("c"), InTopic("d"), InTopic("e"))
+			Ω(c.Err()).ShouldNot(HaveOccurred())
+
+			_, err := c.And(InTopic("f").Not()).Build()
+			Ω(errors.Is(err, ErrInvalidCondition)).Should(BeTrue())
+		})
+
+		It("should fail on nil condition", func() {
+			var nilCond *Condition
+			for _, c := range []*Condition{InTopic("a").And(nil), nilCond.Or(InTopic("a")), NotCondition(nil)} {
+				_, err := c.Build()
+				Ω(errors.Is(err, ErrInvalidCondition)).Should(BeTrue())
+			}
+		})
+	})
+
+	Context("Condition evaluation", func() {
+		cond := "'news' in topics && ('sports' in topics || !('cats' in topics))"
+
+		table.DescribeTable("EvaluateCondition func",
+			func(topics []string, expected bool) {
+				matched, err := EvaluateCondition(cond, topics)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(matched).Should(Equal(expected))
+			},
+			table.Entry("no topics", nil, false),
+			table.Entry("news only", []string{"news"}, true),
+			table.Entry("news and cats", []string{"news", "cats"}, false),
+			table.Entry("news, cats and sports", []string{"/topics/news", "cats", "sports"}, true),
+		)
+
+		It("should fail on invalid condition", func() {
+			_, err := EvaluateCondition("'news' in", []string{"news"})
+			Ω(errors.Is(err, ErrInvalidCondition)).Should(BeTrue())
+		})
+
+		It("should return matched tokens in bulk", func() {
+			it := NewSubscriptionIterator([]Subscription{
+				{Token: "t1", Topics: []string{"news"}},
+				{Token: "t2", Topics: []string{"news", "cats"}},
+				{Token: "t3", Topics: []string{"sports"}},
+				{Token: "t4", Topics: []string{"news", "sports", "cats"}},
+			})
+
+			tokens, err := EvaluateConditionBulk(cond, it)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(tokens).Should(Equal([]string{"t1", "t4"}))
+		})
+	})
+})
@@ -0,0 +1,313 @@
+package fcm
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidData occurs if value couldn't be encoded to or decoded from the data payload.
+var ErrInvalidData = errors.New("data payload is invalid")
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// EncodeData converts struct to the Message.Data payload. Keys are taken from
+// `fcm` struct tags, field name is used if the tag is missing:
+//
+//	type Order struct {
+//		ID        int64     `fcm:"order_id"`
+//		Paid      bool      `fcm:"paid,omitempty"`
+//		CreatedAt time.Time `fcm:"created_at"`
+//		Items     []Item    `fcm:"items"`
+//		Internal  string    `fcm:"-"`
+//	}
+//
+// Strings, numbers and bools are formatted with strconv, time.Duration with its
+// String method, types implementing encoding.TextMarshaler (e.g. time.Time) as text,
+// nested structs, maps and slices as JSON. Fields of embedded structs and struct
+// pointers are flattened like in encoding/json. Nil pointers and zero values
+// of `omitempty` fields are skipped.
+// Keys reserved by FCM are rejected with ErrReservedDataKey.
+func EncodeData(v interface{}) (map[string]string, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: struct is expected, got %T", ErrInvalidData, v)
+	}
+
+	data := make(map[string]string)
+	if err := encodeDataStruct(data, rv); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DecodeData fills the struct pointed by v with the Message.Data payload,
+// it is the reverse of EncodeData. Fields without the corresponding keys are kept as is,
+// nil embedded pointers to exported structs are allocated only if any of their keys is present.
+func DecodeData(data map[string]string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: non-nil pointer to struct is expected, got %T", ErrInvalidData, v)
+	}
+
+	_, err := decodeDataStruct(data, rv.Elem())
+	return err
+}
+
+// dataField is a struct field mapped to the data payload key
+// or a nil embedded struct pointer if embedded is set.
+type dataField struct {
+	key       string
+	omitEmpty bool
+	embedded  bool
+	value     reflect.Value
+}
+
+// dataFields returns fields of the struct with flattened embedded structs.
+func dataFields(rv reflect.Value) []dataField {
+	var fields []dataField
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("fcm")
+		if tag == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if sf.Anonymous && tag == "" {
+			switch {
+			case sf.Type.Kind() == reflect.Struct:
+				fields = append(fields, dataFields(fv)...)
+				continue
+			case sf.Type.Kind() == reflect.Ptr && sf.Type.Elem().Kind() == reflect.Struct:
+				if !fv.IsNil() {
+					fields = append(fields, dataFields(fv.Elem())...)
+				} else if fv.CanSet() {
+					fields = append(fields, dataField{embedded: true, value: fv})
+				}
+
+				continue
+			}
+		}
+
+		// unexported field
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		key, opts := tag, ""
+		if idx := strings.IndexByte(tag, ','); idx >= 0 {
+			key, opts = tag[:idx], tag[idx+1:]
+		}
+
+		if key == "" {
+			key = sf.Name
+		}
+
+		fields = append(fields, dataField{
+			key:       key,
+			omitEmpty: hasDataTagOption(opts, "omitempty"),
+			value:     fv,
+		})
+	}
+
+	return fields
+}
+
+func hasDataTagOption(opts, name string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func encodeDataStruct(data map[string]string, rv reflect.Value) error {
+	for _, f := range dataFields(rv) {
+		if f.embedded {
+			continue
+		}
+
+		if isReservedDataKey(f.key) {
+			return fmt.Errorf("%w: %q", ErrReservedDataKey, f.key)
+		}
+
+		fv := f.value
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+
+			fv = fv.Elem()
+		}
+
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		s, err := encodeDataValue(fv)
+		if err != nil {
+			return fmt.Errorf("%w: key %q: %v", ErrInvalidData, f.key, err)
+		}
+
+		data[f.key] = s
+	}
+
+	return nil
+}
+
+func encodeDataValue(fv reflect.Value) (string, error) {
+	if fv.Type() == durationType {
+		return time.Duration(fv.Int()).String(), nil
+	}
+
+	if fv.Type().Implements(textMarshalerType) {
+		text, err := fv.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	if fv.CanAddr() && reflect.PtrTo(fv.Type()).Implements(textMarshalerType) {
+		text, err := fv.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'g', -1, fv.Type().Bits()), nil
+	default:
+		b, err := json.Marshal(fv.Interface())
+		return string(b), err
+	}
+}
+
+// decodeDataStruct returns the number of decoded keys.
+func decodeDataStruct(data map[string]string, rv reflect.Value) (int, error) {
+	decoded := 0
+	for _, f := range dataFields(rv) {
+		if f.embedded {
+			embedded := reflect.New(f.value.Type().Elem())
+			n, err := decodeDataStruct(data, embedded.Elem())
+			if err != nil {
+				return decoded, err
+			}
+
+			if n > 0 {
+				f.value.Set(embedded)
+				decoded += n
+			}
+
+			continue
+		}
+
+		s, ok := data[f.key]
+		if !ok {
+			continue
+		}
+
+		fv := f.value
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+
+			fv = fv.Elem()
+		}
+
+		if err := decodeDataValue(s, fv); err != nil {
+			return decoded, fmt.Errorf("%w: key %q: %v", ErrInvalidData, f.key, err)
+		}
+
+		decoded++
+	}
+
+	return decoded, nil
+}
+
+func decodeDataValue(s string, fv reflect.Value) error {
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	if reflect.PtrTo(fv.Type()).Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		fv.SetFloat(n)
+	default:
+		return json.Unmarshal([]byte(s), fv.Addr().Interface())
+	}
+
+	return nil
+}
+
+// isEmptyValue reports whether the value is omitted by `omitempty` option,
+// like in encoding/json empty maps and slices are omitted as well.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
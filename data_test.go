@@ -0,0 +1,148 @@
+package fcm
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type testDataItem struct {
+	SKU   string `json:"sku"`
+	Count int    `json:"count"`
+}
+
+type testDataMeta struct {
+	Source string `fcm:"source"`
+}
+
+type testDataPayload struct {
+	testDataMeta
+	OrderID   int64          `fcm:"order_id"`
+	Price     float64        `fcm:"price"`
+	Paid      bool           `fcm:"paid,omitempty"`
+	Note      *string        `fcm:"note"`
+	CreatedAt time.Time      `fcm:"created_at"`
+	Timeout   time.Duration  `fcm:"timeout"`
+	Items     []testDataItem `fcm:"items"`
+	Kind      string
+	Internal  string `fcm:"-"`
+}
+
+type testDataLevel int
+
+func (l *testDataLevel) MarshalText() ([]byte, error) {
+	return []byte("level-" + strconv.Itoa(int(*l))), nil
+}
+
+func (l *testDataLevel) UnmarshalText(text []byte) error {
+	n, err := strconv.Atoi(strings.TrimPrefix(string(text), "level-"))
+	*l = testDataLevel(n)
+	return err
+}
+
+// TestDataExtra is exported, so the nil embedded pointer could be allocated on decode.
+type TestDataExtra struct {
+	Campaign string `fcm:"campaign"`
+}
+
+type testDataEvent struct {
+	*TestDataExtra
+	Level testDataLevel `fcm:"level"`
+	Count int           `fcm:"count,omitempty,string"`
+}
+
+var _ = Describe("Data payload", func() {
+	createdAt := time.Date(2020, 5, 1, 10, 30, 0, 0, time.UTC)
+	payload := testDataPayload{
+		testDataMeta: testDataMeta{Source: "shop"},
+		OrderID:      42,
+		Price:        9.99,
+		CreatedAt:    createdAt,
+		Timeout:      90 * time.Second,
+		Items:        []testDataItem{{SKU: "a", Count: 2}},
+		Kind:         "order",
+		Internal:     "secret",
+	}
+
+	encoded := map[string]string{
+		"source":     "shop",
+		"order_id":   "42",
+		"price":      "9.99",
+		"created_at": "2020-05-01T10:30:00Z",
+		"timeout":    "1m30s",
+		"items":      `[{"sku":"a","count":2}]`,
+		"Kind":       "order",
+	}
+
+	It("should encode struct", func() {
+		data, err := EncodeData(&payload)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(data).Should(Equal(encoded))
+	})
+
+	It("should decode struct", func() {
+		var decoded testDataPayload
+		Ω(DecodeData(encoded, &decoded)).Should(Succeed())
+
+		expected := payload
+		expected.Internal = ""
+		Ω(decoded).Should(Equal(expected))
+	})
+
+	It("should check every tag option", func() {
+		data, err := EncodeData(&testDataEvent{Level: 1})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(data).Should(Equal(map[string]string{"level": "level-1"}))
+	})
+
+	It("should use text marshaler with pointer receiver", func() {
+		event := testDataEvent{Level: 3, Count: 2}
+		data, err := EncodeData(&event)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(data).Should(Equal(map[string]string{"level": "level-3", "count": "2"}))
+
+		var decoded testDataEvent
+		Ω(DecodeData(data, &decoded)).Should(Succeed())
+		Ω(decoded).Should(Equal(event))
+	})
+
+	It("should flatten embedded struct pointers", func() {
+		event := testDataEvent{TestDataExtra: &TestDataExtra{Campaign: "spring"}, Level: 1}
+		data, err := EncodeData(&event)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(data).Should(Equal(map[string]string{"campaign": "spring", "level": "level-1"}))
+
+		var decoded testDataEvent
+		Ω(DecodeData(data, &decoded)).Should(Succeed())
+		Ω(decoded).Should(Equal(event))
+
+		decoded = testDataEvent{}
+		Ω(DecodeData(map[string]string{"level": "level-1"}, &decoded)).Should(Succeed())
+		Ω(decoded.TestDataExtra).Should(BeNil())
+	})
+
+	It("should reject reserved keys", func() {
+		_, err := EncodeData(struct {
+			From string `fcm:"from"`
+		}{})
+		Ω(errors.Is(err, ErrReservedDataKey)).Should(BeTrue())
+	})
+
+	It("should fail on malformed values", func() {
+		var decoded testDataPayload
+		err := DecodeData(map[string]string{"order_id": "abc"}, &decoded)
+		Ω(errors.Is(err, ErrInvalidData)).Should(BeTrue())
+	})
+
+	It("should fail on non struct values", func() {
+		_, err := EncodeData("value")
+		Ω(errors.Is(err, ErrInvalidData)).Should(BeTrue())
+
+		err = DecodeData(nil, testDataPayload{})
+		Ω(errors.Is(err, ErrInvalidData)).Should(BeTrue())
+	})
+})
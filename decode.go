@@ -0,0 +1,218 @@
+package fcm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownField occurs if strictly decoded message has a field unknown to FCM.
+	ErrUnknownField = errors.New("field is unknown")
+
+	// ErrDuplicateField occurs if strictly decoded message has the field set under
+	// both of its names, e.g. "click_action" and "clickAction".
+	ErrDuplicateField = errors.New("field is set more than once")
+)
+
+// fieldAliases maps names used by Admin SDKs to JSON names of the fields per type.
+var fieldAliases = map[reflect.Type]map[string]string{
+	reflect.TypeOf(Notification{}):        {"imageUrl": "image"},
+	reflect.TypeOf(AndroidNotification{}): {"imageUrl": "image"},
+}
+
+// jsonFieldsCache holds jsonFields of the types.
+var jsonFieldsCache sync.Map // reflect.Type -> map[string]reflect.StructField
+
+// DecodeMessage decodes the message from JSON produced by other services.
+// Both JSON names of the API and camelCase names used by Admin SDKs are accepted,
+// e.g. "click_action" and "clickAction". The message may be wrapped into
+// a send request {"message": {...}}.
+//
+// Unknown fields are dropped, in strict mode they are reported instead and the
+// message is validated. Enum values are validated in both modes.
+// Violations are returned as ValidationErrors with paths of the fields.
+func DecodeMessage(r io.Reader, strict bool) (*Message, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode message: %w", err)
+	}
+
+	if wrapped, ok := doc["message"].(map[string]interface{}); ok && len(doc) == 1 {
+		doc = wrapped
+	}
+
+	var v validator
+	normalizeFields(&v, "message", doc, reflect.TypeOf(Message{}), strict)
+
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode message: %w", err)
+	}
+
+	var msg Message
+	if err := msg.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("failed to decode message: %w", err)
+	}
+
+	v.message(&msg)
+	if !strict {
+		// only unknown enum values are rejected in lenient mode
+		enumErrs := v.errs[:0]
+		for _, err := range v.errs {
+			if errors.Is(err, ErrInvalidEnum) {
+				enumErrs = append(enumErrs, err)
+			}
+		}
+
+		v.errs = enumErrs
+	}
+
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+// normalizeFields renames aliased fields of the JSON object to their JSON names
+// and removes unknown fields reporting them in strict mode. If the field is set
+// under several names, the JSON name wins and the duplicates are reported in strict mode.
+func normalizeFields(v *validator, path string, obj map[string]interface{}, t reflect.Type, strict bool) {
+	fields := jsonFields(t)
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+
+	// sorted to report violations in stable order
+	sort.Strings(keys)
+
+	names := make(map[string]string, len(keys))  // key -> JSON name, empty if unknown
+	chosen := make(map[string]string, len(keys)) // JSON name -> key used for it
+	for _, key := range keys {
+		name := key
+		if _, ok := fields[name]; !ok {
+			if name, ok = fieldAliases[t][key]; !ok {
+				name = camelToSnake(key)
+			}
+
+			if _, ok = fields[name]; !ok {
+				continue
+			}
+		}
+
+		names[key] = name
+		if _, ok := chosen[name]; !ok || key == name {
+			chosen[name] = key
+		}
+	}
+
+	for _, key := range keys {
+		value := obj[key]
+		name, ok := names[key]
+		if !ok {
+			if strict {
+				v.add(path+"."+key, ErrUnknownField)
+			}
+
+			delete(obj, key)
+			continue
+		}
+
+		if chosen[name] != key {
+			if strict {
+				v.addf(path+"."+key, ErrDuplicateField, "%q is also set", chosen[name])
+			}
+
+			delete(obj, key)
+			continue
+		}
+
+		if name != key {
+			delete(obj, key)
+			obj[name] = value
+		}
+
+		normalizeValue(v, path+"."+name, value, fields[name].Type, strict)
+	}
+}
+
+func normalizeValue(v *validator, path string, value interface{}, t reflect.Type, strict bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if obj, ok := value.(map[string]interface{}); ok {
+			normalizeFields(v, path, obj, t, strict)
+		}
+	case reflect.Slice:
+		if list, ok := value.([]interface{}); ok {
+			for i, item := range list {
+				normalizeValue(v, fmt.Sprintf("%s[%d]", path, i), item, t.Elem(), strict)
+			}
+		}
+	}
+}
+
+// jsonFields returns fields of the struct type by their JSON names.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	if fields, ok := jsonFieldsCache.Load(t); ok {
+		return fields.(map[string]reflect.StructField)
+	}
+
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" || f.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[name] = f
+	}
+
+	jsonFieldsCache.Store(t, fields)
+	return fields
+}
+
+// camelToSnake converts camelCase name to snake_case, e.g. "lightOnDuration" to "light_on_duration".
+func camelToSnake(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'A' && c <= 'Z' {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+
+			c += 'a' - 'A'
+		}
+
+		sb.WriteByte(c)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,100 @@
+package fcm
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DecodeMessage", func() {
+	It("should decode camelCase message", func() {
+		msg, err := DecodeMessage(strings.NewReader(`{"message": {
+			"token": "token",
+			"notification": {"title": "Hi", "imageUrl": "https://example.com/a.png"},
+			"android": {
+				"collapseKey": "key",
+				"ttl": "3600s",
+				"fcmOptions": {"analyticsLabel": "label"},
+				"notification": {"clickAction": "OPEN", "channel_id": "news", "notificationPriority": "PRIORITY_HIGH", "color": "#ff0000",
+					"lightSettings": {"color": {"red": 1}, "lightOnDuration": "1s", "lightOffDuration": "2s"}}
+			}
+		}}`), true)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(msg).Should(Equal(&Message{
+			Token:        "token",
+			Notification: &Notification{Title: "Hi", Image: "https://example.com/a.png"},
+			Android: &AndroidConfig{
+				CollapseKey: "key",
+				Ttl:         NewDuration(time.Hour),
+				FCMOptions:  &AndroidFCMOptions{AnalyticsLabel: "label"},
+				Notification: &AndroidNotification{
+					ClickAction:          "OPEN",
+					ChannelId:            "news",
+					NotificationPriority: NotificationPriorityHigh,
+					Color:                "#ff0000",
+					LightSettings: &LightSettings{
+						Color:            Color{Red: 1},
+						LightOnDuration:  NewDuration(time.Second),
+						LightOffDuration: NewDuration(2 * time.Second),
+					},
+				},
+			},
+		}))
+	})
+
+	table.DescribeTable("violations",
+		func(strict bool, input string, paths ...string) {
+			_, err := DecodeMessage(strings.NewReader(input), strict)
+			if len(paths) == 0 {
+				Ω(err).ShouldNot(HaveOccurred())
+				return
+			}
+
+			var validationErrs ValidationErrors
+			Ω(errors.As(err, &validationErrs)).Should(BeTrue())
+
+			var actual []string
+			for _, e := range validationErrs {
+				actual = append(actual, e.Path)
+			}
+
+			Ω(actual).Should(Equal(paths))
+		},
+		table.Entry("unknown fields in strict mode", true,
+			`{"token": "t", "foo": 1, "android": {"notification": {"bar": true}, "vibrate_timings": []}}`,
+			"message.android.notification.bar", "message.android.vibrate_timings", "message.foo"),
+		table.Entry("unknown fields in lenient mode", false,
+			`{"token": "t", "foo": 1, "android": {"notification": {"bar": true}}}`),
+		table.Entry("invalid enum in lenient mode", false,
+			`{"android": {"priority": "URGENT"}}`,
+			"message.android.priority"),
+		table.Entry("duplicate fields in strict mode", true,
+			`{"token": "t", "android": {"notification": {"clickAction": "a", "click_action": "b"}}}`,
+			"message.android.notification.clickAction"),
+		table.Entry("duplicate fields in lenient mode", false,
+			`{"token": "t", "android": {"notification": {"clickAction": "a", "click_action": "b"}}}`),
+		table.Entry("missing target in strict mode", true,
+			`{"notification": {"title": "t"}}`,
+			"message"),
+	)
+
+	It("should prefer JSON name of the duplicate field", func() {
+		msg, err := DecodeMessage(strings.NewReader(
+			`{"token": "t", "android": {"notification": {"clickAction": "a", "click_action": "b"}}}`), false)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(msg.Android.Notification.ClickAction).Should(Equal("b"))
+	})
+
+	It("should fail on malformed JSON", func() {
+		_, err := DecodeMessage(strings.NewReader(`{"token": 1}`), false)
+		Ω(err).Should(HaveOccurred())
+
+		_, err = DecodeMessage(strings.NewReader(`[]`), false)
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,126 @@
+package fcm
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// MaxTTL is the maximum message time to live accepted by FCM.
+const MaxTTL = 4 * 7 * 24 * time.Hour
+
+var durationRegexp = regexp.MustCompile(`^(-)?(\d+)(?:\.(\d{1,9}))?s$`)
+
+// Duration is a time.Duration marshalled to JSON in protobuf Duration format:
+// seconds with up to nine fractional digits and "s" suffix, e.g. "3.5s".
+// See https://developers.google.com/protocol-buffers/docs/reference/google.protobuf#duration
+type Duration time.Duration
+
+// NewDuration returns pointer to the Duration, handy for optional fields like AndroidConfig.Ttl.
+func NewDuration(d time.Duration) *Duration {
+	pd := Duration(d)
+	return &pd
+}
+
+// ParseDuration parses duration in protobuf Duration format.
+func ParseDuration(s string) (Duration, error) {
+	m := durationRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	}
+
+	seconds, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil || seconds > math.MaxInt64/int64(time.Second)-1 {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidDuration, s)
+	}
+
+	var nanos int64
+	if m[3] != "" {
+		// pad fraction to nanoseconds, e.g. ".5" -> 500000000
+		nanos, _ = strconv.ParseInt(m[3]+strings.Repeat("0", 9-len(m[3])), 10, 64)
+	}
+
+	d := time.Duration(seconds)*time.Second + time.Duration(nanos)
+	if m[1] == "-" {
+		d = -d
+	}
+
+	return Duration(d), nil
+}
+
+// Duration returns the value as time.Duration.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String formats the duration in protobuf Duration format. Like protobuf JSON
+// encoding it uses 0, 3, 6 or 9 fractional digits, e.g. "1s", "1.500s".
+func (d Duration) String() string {
+	sign := ""
+	ns := int64(d)
+	if ns < 0 {
+		sign = "-"
+	}
+
+	seconds := ns / int64(time.Second)
+	nanos := ns % int64(time.Second)
+	if seconds < 0 {
+		seconds = -seconds
+	}
+
+	if nanos < 0 {
+		nanos = -nanos
+	}
+
+	switch {
+	case nanos == 0:
+		return fmt.Sprintf("%s%ds", sign, seconds)
+	case nanos%int64(time.Millisecond) == 0:
+		return fmt.Sprintf("%s%d.%03ds", sign, seconds, nanos/int64(time.Millisecond))
+	case nanos%int64(time.Microsecond) == 0:
+		return fmt.Sprintf("%s%d.%06ds", sign, seconds, nanos/int64(time.Microsecond))
+	default:
+		return fmt.Sprintf("%s%d.%09ds", sign, seconds, nanos)
+	}
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (d Duration) MarshalEasyJSON(w *jwriter.Writer) {
+	w.String(d.String())
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (d *Duration) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	s := l.String()
+	if !l.Ok() {
+		return
+	}
+
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		l.AddError(err)
+		return
+	}
+
+	*d = parsed
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (d Duration) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	d.MarshalEasyJSON(&w)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	d.UnmarshalEasyJSON(&r)
+	return r.Error()
+}
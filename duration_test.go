@@ -0,0 +1,70 @@
+package fcm
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Duration", func() {
+	table.DescribeTable("format and parse",
+		func(d time.Duration, formatted string) {
+			Ω(Duration(d).String()).Should(Equal(formatted))
+
+			parsed, err := ParseDuration(formatted)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(parsed.Duration()).Should(Equal(d))
+		},
+		table.Entry("zero", time.Duration(0), "0s"),
+		table.Entry("seconds", 4*7*24*time.Hour, "2419200s"),
+		table.Entry("milliseconds", 3500*time.Millisecond, "3.500s"),
+		table.Entry("microseconds", 1500*time.Microsecond, "0.001500s"),
+		table.Entry("nanoseconds", time.Second+time.Nanosecond, "1.000000001s"),
+		table.Entry("negative", -1500*time.Millisecond, "-1.500s"),
+		table.Entry("negative fraction", -time.Millisecond, "-0.001s"),
+	)
+
+	It("should parse short fraction", func() {
+		d, err := ParseDuration("3.5s")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(d.Duration()).Should(Equal(3500 * time.Millisecond))
+	})
+
+	table.DescribeTable("invalid durations",
+		func(s string) {
+			_, err := ParseDuration(s)
+			Ω(errors.Is(err, ErrInvalidDuration)).Should(BeTrue())
+		},
+		table.Entry("empty", ""),
+		table.Entry("no suffix", "10"),
+		table.Entry("go format", "1m30s"),
+		table.Entry("too precise", "1.0000000001s"),
+		table.Entry("overflow", "9223372036854775807s"),
+	)
+
+	It("should marshal message fields", func() {
+		msg := Message{Android: &AndroidConfig{
+			Ttl: NewDuration(0),
+			Notification: &AndroidNotification{
+				VibrateTimings: []Duration{Duration(time.Second), Duration(time.Second / 2)},
+			},
+		}}
+
+		data, err := msg.MarshalJSON()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(data)).Should(Equal(`{"android":{"ttl":"0s","notification":{"vibrate_timings":["1s","0.500s"]}}}`))
+
+		var decoded Message
+		Ω(decoded.UnmarshalJSON(data)).Should(Succeed())
+		Ω(decoded).Should(Equal(msg))
+	})
+
+	It("should fail to unmarshal malformed duration", func() {
+		var decoded Message
+		err := decoded.UnmarshalJSON([]byte(`{"android":{"ttl":"1h"}}`))
+		Ω(errors.Is(err, ErrInvalidDuration)).Should(BeTrue())
+	})
+})
@@ -3,6 +3,13 @@ package fcm
 // This error can be caused by missing registration tokens, unregistered or expired tokens.
 const ErrUnregistered Error = "Unregistered"
 
+// This error is caused by malformed registration token, the request has INVALID_ARGUMENT
+// error code with the field violation of the message token.
+const ErrInvalidToken Error = "InvalidToken"
+
+// This error occurs if the registration token is tied to another sender.
+const ErrSenderIDMismatch Error = "SenderIdMismatch"
+
 type Error string
 
 func (e Error) Error() string {
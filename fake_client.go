@@ -0,0 +1,161 @@
+package fcm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var _ Client = (*FakeClient)(nil)
+
+// FakeCall is a send recorded by FakeClient.
+type FakeCall struct {
+	Message *Message
+	Options SendOptions
+	Err     error
+}
+
+type fakeFailure struct {
+	match func(msg *Message) bool
+	err   error
+}
+
+// FakeClient is Client for tests which records messages instead of sending them.
+// Messages are validated like SimpleClient does, errors are injected by FailWhen.
+// It is safe for concurrent use.
+type FakeClient struct {
+	mu       sync.Mutex
+	calls    []FakeCall
+	failures []fakeFailure
+}
+
+// NewFakeClient returns FakeClient sending all valid messages successfully.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{}
+}
+
+// FailWhen makes sends of messages matching the predicate fail with the error.
+// Predicates are checked in the order they are added.
+func (c *FakeClient) FailWhen(match func(msg *Message) bool, err error) *FakeClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures = append(c.failures, fakeFailure{match: match, err: err})
+	return c
+}
+
+// FailToken makes sends to the registration token fail with the error, e.g. ErrUnregistered.
+func (c *FakeClient) FailToken(token string, err error) *FakeClient {
+	return c.FailWhen(func(msg *Message) bool {
+		return msg.Token == token
+	}, err)
+}
+
+// Send implementation of Client interface.
+// Validate only sends are recorded to Calls but they are not considered as sent.
+func (c *FakeClient) Send(ctx context.Context, msg *Message, opts ...SendOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var err error
+	if err = msg.Validate(); err != nil {
+		err = fmt.Errorf("invalid message: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		for _, f := range c.failures {
+			if f.match(msg) {
+				err = f.err
+				break
+			}
+		}
+	}
+
+	c.calls = append(c.calls, FakeCall{Message: msg.Clone(), Options: ApplySendOptions(opts...), Err: err})
+	return err
+}
+
+// Calls returns all sends including failed ones in the order they are made.
+func (c *FakeClient) Calls() []FakeCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	calls := make([]FakeCall, len(c.calls))
+	for i, call := range c.calls {
+		calls[i] = FakeCall{Message: call.Message.Clone(), Options: call.Options, Err: call.Err}
+	}
+
+	return calls
+}
+
+// Sent returns successfully sent messages.
+func (c *FakeClient) Sent() []*Message {
+	return c.filter(func(*Message) bool { return true })
+}
+
+// SentTo returns messages successfully sent to the registration token.
+func (c *FakeClient) SentTo(token string) []*Message {
+	return c.filter(func(msg *Message) bool {
+		return msg.Token == token
+	})
+}
+
+// SentToTopic returns messages successfully sent to the topic, "/topics/" prefix is optional.
+func (c *FakeClient) SentToTopic(topic string) []*Message {
+	topic = strings.TrimPrefix(topic, topicsPrefix)
+	return c.filter(func(msg *Message) bool {
+		return msg.Topic != "" && strings.TrimPrefix(msg.Topic, topicsPrefix) == topic
+	})
+}
+
+// SentToCondition returns messages successfully sent to the condition.
+func (c *FakeClient) SentToCondition(cond string) []*Message {
+	return c.filter(func(msg *Message) bool {
+		return msg.Condition == cond
+	})
+}
+
+// Last returns the last successfully sent message, nil if there are none.
+func (c *FakeClient) Last() *Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := len(c.calls) - 1; i >= 0; i-- {
+		if c.calls[i].delivered() {
+			return c.calls[i].Message.Clone()
+		}
+	}
+
+	return nil
+}
+
+// Reset forgets recorded sends, injected errors are kept.
+func (c *FakeClient) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = nil
+}
+
+func (c *FakeClient) filter(match func(msg *Message) bool) []*Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var messages []*Message
+	for _, call := range c.calls {
+		if call.delivered() && match(call.Message) {
+			messages = append(messages, call.Message.Clone())
+		}
+	}
+
+	return messages
+}
+
+func (call FakeCall) delivered() bool {
+	return call.Err == nil && !call.Options.ValidateOnly
+}
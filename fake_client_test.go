@@ -0,0 +1,80 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FakeClient", func() {
+	var client *FakeClient
+
+	BeforeEach(func() {
+		client = NewFakeClient().FailToken("stale", ErrUnregistered)
+	})
+
+	table.DescribeTable("Send func",
+		func(msg *Message, expectedErr error) {
+			err := client.Send(context.Background(), msg)
+			if expectedErr == nil {
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(client.Last()).Should(Equal(msg))
+			} else {
+				Ω(errors.Is(err, expectedErr)).Should(BeTrue())
+				Ω(client.Last()).Should(BeNil())
+			}
+
+			Ω(client.Calls()).Should(HaveLen(1))
+		},
+		table.Entry("token", &Message{Token: "token"}, nil),
+		table.Entry("topic", &Message{Topic: "news"}, nil),
+		table.Entry("stale token", &Message{Token: "stale"}, ErrUnregistered),
+		table.Entry("invalid message", &Message{}, ErrInvalidTarget),
+	)
+
+	It("should query sent messages", func() {
+		client.FailWhen(func(msg *Message) bool {
+			return msg.Data["fail"] == "true"
+		}, errors.New("unavailable"))
+
+		var wg sync.WaitGroup
+		for _, msg := range []*Message{
+			{Token: "a"},
+			{Token: "a", Data: map[string]string{"fail": "true"}},
+			{Token: "b"},
+			{Token: "stale"},
+			{Topic: "/topics/news"},
+			{Condition: "'a' in topics"},
+		} {
+			wg.Add(1)
+			go func(msg *Message) {
+				defer wg.Done()
+				_ = client.Send(context.Background(), msg)
+			}(msg)
+		}
+
+		wg.Wait()
+		Ω(client.Calls()).Should(HaveLen(6))
+		Ω(client.Sent()).Should(HaveLen(4))
+		Ω(client.SentTo("a")).Should(Equal([]*Message{{Token: "a"}}))
+		Ω(client.SentTo("stale")).Should(BeEmpty())
+		Ω(client.SentToTopic("news")).Should(HaveLen(1))
+		Ω(client.SentToCondition("'a' in topics")).Should(HaveLen(1))
+
+		client.Reset()
+		Ω(client.Sent()).Should(BeEmpty())
+	})
+
+	It("should not share recorded messages", func() {
+		msg := &Message{Token: "token", Data: map[string]string{"k": "v"}}
+		Ω(client.Send(context.Background(), msg)).Should(Succeed())
+
+		msg.Data["k"] = "changed"
+		client.Last().Data["k"] = "changed too"
+		Ω(client.Last().Data).Should(Equal(map[string]string{"k": "v"}))
+	})
+})
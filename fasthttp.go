@@ -13,6 +13,8 @@ type FastHTTPDoer interface {
 
 var _ FastHTTPDoer = (*FastHTTPAdapter)(nil)
 
+// FastHTTPAdapter performs requests with fasthttp.Client. The pinned fasthttp version
+// can't close idle connections on demand, they are closed after Client.MaxIdleConnDuration.
 type FastHTTPAdapter struct {
 	Client *fasthttp.Client
 }
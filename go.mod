@@ -12,4 +12,5 @@ require (
 	github.com/onsi/gomega v1.10.3
 	github.com/valyala/fasthttp v1.14.0
 	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
+	gopkg.in/yaml.v2 v2.3.0
 )
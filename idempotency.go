@@ -0,0 +1,259 @@
+package fcm
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey returns context carrying the deduplication key of the send,
+// an alternative to WithSendIdempotencyKey for sends made by other components.
+// IdempotentClient sends the message only once for the same key within its window.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// IdempotencyKeyFromContext returns the deduplication key set by WithIdempotencyKey.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyCtx{}).(string)
+	return key, ok && key != ""
+}
+
+// IdempotencyRecord is a cached result of the send, Err is nil on success.
+type IdempotencyRecord struct {
+	Err    error
+	SentAt time.Time
+}
+
+// IdempotencyCache stores results of sends by deduplication key.
+// Implementations must be safe for concurrent use.
+type IdempotencyCache interface {
+	// Get returns the record if it is present and not expired.
+	Get(key string) (IdempotencyRecord, bool)
+	// Set stores the record for the ttl.
+	Set(key string, rec IdempotencyRecord, ttl time.Duration)
+}
+
+var (
+	_ Client           = (*IdempotentClient)(nil)
+	_ IdempotencyCache = (*LRUIdempotencyCache)(nil)
+)
+
+// IdempotentClient wraps Client to make sends with the same idempotency key
+// happen only once within the window, so retries upstream don't cause duplicate
+// pushes. Repeated sends return the cached result. Only successful sends and
+// permanent errors are cached, sends failed with temporary errors may be retried.
+// Sends without the key are passed as is.
+type IdempotentClient struct {
+	client Client
+	cache  IdempotencyCache
+	window time.Duration
+	clock  Clock
+
+	mu       sync.Mutex // guards inflight only, the cache is safe for concurrent use
+	inflight map[string]*idempotentCall
+}
+
+type idempotentCall struct {
+	done chan struct{}
+	err  error
+	// interrupted is true if the send failed because its context was done,
+	// so waiting sends should try again with their own contexts.
+	interrupted bool
+}
+
+// IdempotencyOption configures IdempotentClient with defined option.
+type IdempotencyOption func(*IdempotentClient)
+
+// WithIdempotencyCache returns IdempotencyOption to configure the cache,
+// e.g. a shared one for multiple instances of the service.
+func WithIdempotencyCache(cache IdempotencyCache) IdempotencyOption {
+	return func(c *IdempotentClient) {
+		c.cache = cache
+	}
+}
+
+// WithIdempotencyWindow returns IdempotencyOption to configure how long results are cached.
+func WithIdempotencyWindow(window time.Duration) IdempotencyOption {
+	return func(c *IdempotentClient) {
+		c.window = window
+	}
+}
+
+// WithIdempotencyClock returns IdempotencyOption to configure the clock.
+func WithIdempotencyClock(clock Clock) IdempotencyOption {
+	return func(c *IdempotentClient) {
+		c.clock = clock
+	}
+}
+
+// NewIdempotentClient wraps the client. By default results are cached for an hour
+// in LRUIdempotencyCache holding 10000 keys.
+func NewIdempotentClient(client Client, opts ...IdempotencyOption) *IdempotentClient {
+	c := IdempotentClient{
+		client:   client,
+		window:   time.Hour,
+		clock:    RealClock,
+		inflight: make(map[string]*idempotentCall),
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if c.cache == nil {
+		c.cache = NewLRUIdempotencyCache(10000, c.clock)
+	}
+
+	return &c
+}
+
+// Send implementation of Client interface. The key is taken from WithSendIdempotencyKey
+// option or the context. Concurrent sends with the same key wait for the first one
+// and share its result, unless it is interrupted by its context, then one of them
+// sends the message again.
+func (c *IdempotentClient) Send(ctx context.Context, msg *Message, opts ...SendOption) error {
+	key := ApplySendOptions(opts...).IdempotencyKey
+	if key == "" {
+		key, _ = IdempotencyKeyFromContext(ctx)
+	}
+
+	if key == "" {
+		return c.client.Send(ctx, msg, opts...)
+	}
+
+	for {
+		if rec, ok := c.cache.Get(key); ok {
+			return rec.Err
+		}
+
+		c.mu.Lock()
+		call, ok := c.inflight[key]
+		if !ok {
+			call = &idempotentCall{done: make(chan struct{})}
+			c.inflight[key] = call
+			c.mu.Unlock()
+			return c.lead(ctx, key, call, msg, opts)
+		}
+
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+			if !call.interrupted {
+				return call.err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// lead sends the message on behalf of all concurrent sends with the key.
+func (c *IdempotentClient) lead(ctx context.Context, key string, call *idempotentCall, msg *Message,
+	opts []SendOption) error {
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	// the previous leader could finish between the cache check and registration
+	if rec, ok := c.cache.Get(key); ok {
+		call.err = rec.Err
+		return call.err
+	}
+
+	call.err = c.client.Send(ctx, msg, opts...)
+	call.interrupted = call.err != nil && ctx.Err() != nil
+	if call.err == nil || isPermanentSendError(call.err) {
+		c.cache.Set(key, IdempotencyRecord{Err: call.err, SentAt: c.clock.Now()}, c.window)
+	}
+
+	return call.err
+}
+
+// LRUIdempotencyCache is in-memory IdempotencyCache evicting the least recently
+// used keys when the capacity is exceeded.
+type LRUIdempotencyCache struct {
+	capacity int
+	clock    Clock
+
+	mu    sync.Mutex
+	items map[string]*list.Element
+	order *list.List
+}
+
+type lruItem struct {
+	key       string
+	rec       IdempotencyRecord
+	expiresAt time.Time
+}
+
+// NewLRUIdempotencyCache returns an empty cache holding at most capacity keys.
+// RealClock is used if clock is nil.
+func NewLRUIdempotencyCache(capacity int, clock Clock) *LRUIdempotencyCache {
+	if clock == nil {
+		clock = RealClock
+	}
+
+	return &LRUIdempotencyCache{
+		capacity: capacity,
+		clock:    clock,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *LRUIdempotencyCache) Get(key string) (IdempotencyRecord, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return IdempotencyRecord{}, false
+	}
+
+	item := el.Value.(*lruItem)
+	if !c.clock.Now().Before(item.expiresAt) {
+		c.remove(el)
+		return IdempotencyRecord{}, false
+	}
+
+	c.order.MoveToFront(el)
+	return item.rec, true
+}
+
+func (c *LRUIdempotencyCache) Set(key string, rec IdempotencyRecord, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.clock.Now().Add(ttl)
+	if el, ok := c.items[key]; ok {
+		item := el.Value.(*lruItem)
+		item.rec, item.expiresAt = rec, expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruItem{key: key, rec: rec, expiresAt: expiresAt})
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Len returns number of cached keys including expired ones not evicted yet.
+func (c *LRUIdempotencyCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRUIdempotencyCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*lruItem).key)
+}
@@ -0,0 +1,161 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IdempotentClient", func() {
+	var (
+		mock   *ClientMock
+		clock  *ManualClock
+		client *IdempotentClient
+		msg    *Message
+	)
+
+	BeforeEach(func() {
+		mock = NewClientMock(GinkgoT())
+		clock = NewManualClock(time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC))
+		client = NewIdempotentClient(mock, WithIdempotencyClock(clock), WithIdempotencyWindow(time.Minute))
+		msg = &Message{Token: "token"}
+	})
+
+	It("should send once per key within the window", func() {
+		mock.SendMock.Return(nil)
+		ctx := WithIdempotencyKey(context.Background(), "order-1")
+
+		Ω(client.Send(ctx, msg)).Should(Succeed())
+		Ω(client.Send(ctx, msg)).Should(Succeed())
+		Ω(mock.SendAfterCounter()).Should(BeEquivalentTo(1))
+
+		clock.Advance(time.Minute)
+		Ω(client.Send(ctx, msg)).Should(Succeed())
+		Ω(mock.SendAfterCounter()).Should(BeEquivalentTo(2))
+
+		Ω(client.Send(context.Background(), msg)).Should(Succeed())
+		Ω(mock.SendAfterCounter()).Should(BeEquivalentTo(3))
+	})
+
+	It("should cache permanent errors only", func() {
+		ctx := WithIdempotencyKey(context.Background(), "order-1")
+
+		mock.SendMock.Return(errors.New("unavailable"))
+		Ω(client.Send(ctx, msg)).ShouldNot(Succeed())
+
+		mock.SendMock.Return(ErrUnregistered)
+		Ω(client.Send(ctx, msg)).Should(MatchError(ErrUnregistered))
+
+		mock.SendMock.Return(nil)
+		Ω(client.Send(ctx, msg)).Should(MatchError(ErrUnregistered))
+		Ω(mock.SendAfterCounter()).Should(BeEquivalentTo(2))
+	})
+
+	It("should share result of concurrent sends", func() {
+		release := make(chan struct{})
+		mock.SendMock.Set(func(context.Context, *Message, ...SendOption) error {
+			<-release
+			return nil
+		})
+
+		ctx := WithIdempotencyKey(context.Background(), "order-1")
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Ω(client.Send(ctx, msg)).Should(Succeed())
+			}()
+		}
+
+		Eventually(mock.SendBeforeCounter).Should(BeEquivalentTo(1))
+		close(release)
+		wg.Wait()
+		Ω(mock.SendAfterCounter()).Should(BeEquivalentTo(1))
+	})
+
+	It("should retry waiting sends if the first one is canceled", func() {
+		mock.SendMock.Set(func(ctx context.Context, _ *Message, _ ...SendOption) error {
+			if mock.SendBeforeCounter() == 1 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(WithIdempotencyKey(context.Background(), "order-1"))
+		first := make(chan error, 1)
+		go func() {
+			first <- client.Send(ctx, msg)
+		}()
+
+		Eventually(mock.SendBeforeCounter).Should(BeEquivalentTo(1))
+		second := make(chan error, 1)
+		go func() {
+			second <- client.Send(WithIdempotencyKey(context.Background(), "order-1"), msg)
+		}()
+
+		Consistently(second, 20*time.Millisecond).ShouldNot(Receive())
+		cancel()
+		Eventually(first).Should(Receive(Equal(context.Canceled)))
+		Eventually(second).Should(Receive(BeNil()))
+		Ω(mock.SendAfterCounter()).Should(BeEquivalentTo(2))
+	})
+
+	It("should not block other keys on the cache", func() {
+		cache := &blockingCache{IdempotencyCache: NewLRUIdempotencyCache(10, clock), key: "slow",
+			release: make(chan struct{})}
+		client = NewIdempotentClient(mock, WithIdempotencyCache(cache))
+		mock.SendMock.Return(nil)
+
+		slow := make(chan error, 1)
+		go func() {
+			slow <- client.Send(context.Background(), msg, WithSendIdempotencyKey("slow"))
+		}()
+
+		Ω(client.Send(context.Background(), msg, WithSendIdempotencyKey("fast"))).Should(Succeed())
+		Consistently(slow, 20*time.Millisecond).ShouldNot(Receive())
+		close(cache.release)
+		Eventually(slow).Should(Receive(BeNil()))
+	})
+})
+
+// blockingCache blocks Get of the key until released.
+type blockingCache struct {
+	IdempotencyCache
+	key     string
+	release chan struct{}
+}
+
+func (c *blockingCache) Get(key string) (IdempotencyRecord, bool) {
+	if key == c.key {
+		<-c.release
+	}
+
+	return c.IdempotencyCache.Get(key)
+}
+
+var _ = Describe("LRUIdempotencyCache", func() {
+	It("should evict least recently used keys", func() {
+		cache := NewLRUIdempotencyCache(2, nil)
+		cache.Set("a", IdempotencyRecord{}, time.Hour)
+		cache.Set("b", IdempotencyRecord{}, time.Hour)
+
+		_, ok := cache.Get("a")
+		Ω(ok).Should(BeTrue())
+
+		cache.Set("c", IdempotencyRecord{}, time.Hour)
+		Ω(cache.Len()).Should(Equal(2))
+
+		_, ok = cache.Get("b")
+		Ω(ok).Should(BeFalse())
+		_, ok = cache.Get("a")
+		Ω(ok).Should(BeTrue())
+	})
+})
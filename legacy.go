@@ -0,0 +1,227 @@
+package fcm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LegacyMessage is a payload of the legacy FCM HTTP API,
+// see https://firebase.google.com/docs/cloud-messaging/http-server-ref
+type LegacyMessage struct {
+	To                    string                 `json:"to,omitempty"`
+	RegistrationIDs       []string               `json:"registration_ids,omitempty"`
+	Condition             string                 `json:"condition,omitempty"`
+	CollapseKey           string                 `json:"collapse_key,omitempty"`
+	Priority              string                 `json:"priority,omitempty"`
+	ContentAvailable      bool                   `json:"content_available,omitempty"`
+	MutableContent        bool                   `json:"mutable_content,omitempty"`
+	TimeToLive            *int                   `json:"time_to_live,omitempty"`
+	RestrictedPackageName string                 `json:"restricted_package_name,omitempty"`
+	DryRun                bool                   `json:"dry_run,omitempty"`
+	Data                  map[string]interface{} `json:"data,omitempty"`
+	Notification          *LegacyNotification    `json:"notification,omitempty"`
+}
+
+// LegacyNotification is a notification payload of the legacy FCM HTTP API.
+type LegacyNotification struct {
+	Title            string   `json:"title,omitempty"`
+	Body             string   `json:"body,omitempty"`
+	AndroidChannelID string   `json:"android_channel_id,omitempty"`
+	Icon             string   `json:"icon,omitempty"`
+	Image            string   `json:"image,omitempty"`
+	Sound            string   `json:"sound,omitempty"`
+	Tag              string   `json:"tag,omitempty"`
+	Color            string   `json:"color,omitempty"`
+	ClickAction      string   `json:"click_action,omitempty"`
+	BodyLocKey       string   `json:"body_loc_key,omitempty"`
+	BodyLocArgs      []string `json:"body_loc_args,omitempty"`
+	TitleLocKey      string   `json:"title_loc_key,omitempty"`
+	TitleLocArgs     []string `json:"title_loc_args,omitempty"`
+	Subtitle         string   `json:"subtitle,omitempty"`
+	Badge            string   `json:"badge,omitempty"`
+}
+
+// LegacyIssue describes the legacy field which can't be mapped to Message as is.
+type LegacyIssue struct {
+	Field  string
+	Reason string
+}
+
+func (i LegacyIssue) String() string {
+	return i.Field + ": " + i.Reason
+}
+
+// LegacyReport lists legacy fields dropped or changed by the conversion.
+type LegacyReport struct {
+	Issues []LegacyIssue
+}
+
+func (r *LegacyReport) add(field, reason string) {
+	r.Issues = append(r.Issues, LegacyIssue{Field: field, Reason: reason})
+}
+
+// ConvertLegacy converts the legacy payload to messages, one per registration ID
+// or a single one for "to" or "condition" target. Fields which have no equivalent
+// on Android, e.g. iOS specific ones, are dropped and listed in the report.
+// Converted messages are validated.
+func ConvertLegacy(legacy *LegacyMessage) ([]*Message, *LegacyReport, error) {
+	if legacy == nil {
+		return nil, nil, ErrInvalidMessage
+	}
+
+	var report LegacyReport
+	tmpl, err := convertLegacyPayload(legacy, &report)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var targets []*Message
+	switch {
+	case len(legacy.RegistrationIDs) > 0 && (legacy.To != "" || legacy.Condition != ""),
+		legacy.To != "" && legacy.Condition != "":
+		return nil, nil, ErrMultipleTargets
+	case len(legacy.RegistrationIDs) > 0:
+		for _, id := range legacy.RegistrationIDs {
+			targets = append(targets, &Message{Token: id})
+		}
+	case strings.HasPrefix(legacy.To, topicsPrefix):
+		targets = []*Message{{Topic: strings.TrimPrefix(legacy.To, topicsPrefix)}}
+	case legacy.To != "":
+		targets = []*Message{{Token: legacy.To}}
+	case legacy.Condition != "":
+		targets = []*Message{{Condition: legacy.Condition}}
+	default:
+		return nil, nil, ErrInvalidTarget
+	}
+
+	messages := make([]*Message, 0, len(targets))
+	for _, target := range targets {
+		msg := tmpl.Clone()
+		msg.Merge(target)
+		if err := msg.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("invalid message: %w", err)
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, &report, nil
+}
+
+// convertLegacyPayload converts all fields except the target.
+func convertLegacyPayload(legacy *LegacyMessage, report *LegacyReport) (*Message, error) {
+	msg := Message{}
+	android := AndroidConfig{
+		CollapseKey:           legacy.CollapseKey,
+		RestrictedPackageName: legacy.RestrictedPackageName,
+	}
+
+	switch strings.ToLower(legacy.Priority) {
+	case "":
+	case "normal", "5":
+		android.Priority = AndroidMessagePriorityNormal
+	case "high", "10":
+		android.Priority = AndroidMessagePriorityHigh
+	default:
+		return nil, fmt.Errorf("%w: priority %q", ErrInvalidEnum, legacy.Priority)
+	}
+
+	if legacy.TimeToLive != nil {
+		android.Ttl = NewDuration(time.Duration(*legacy.TimeToLive) * time.Second)
+	}
+
+	if legacy.ContentAvailable {
+		report.add("content_available", "iOS only, not supported by Android config")
+	}
+
+	if legacy.MutableContent {
+		report.add("mutable_content", "iOS only, not supported by Android config")
+	}
+
+	if legacy.DryRun {
+		report.add("dry_run", "use SimpleClient.SendDryRun instead")
+	}
+
+	if len(legacy.Data) > 0 {
+		msg.Data = make(map[string]string, len(legacy.Data))
+		keys := make([]string, 0, len(legacy.Data))
+		for key := range legacy.Data {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, err := legacyDataValue(legacy.Data[key])
+			if err != nil {
+				return nil, fmt.Errorf("%w: data %q: %v", ErrInvalidData, key, err)
+			}
+
+			if _, ok := legacy.Data[key].(string); !ok {
+				report.add("data."+key, "non-string value is encoded to JSON")
+			}
+
+			msg.Data[key] = value
+		}
+	}
+
+	if n := legacy.Notification; n != nil {
+		if n.Title != "" || n.Body != "" || n.Image != "" {
+			msg.Notification = &Notification{Title: n.Title, Body: n.Body, Image: n.Image}
+		}
+
+		an := AndroidNotification{
+			ChannelId:    n.AndroidChannelID,
+			Icon:         n.Icon,
+			Sound:        n.Sound,
+			Tag:          n.Tag,
+			Color:        n.Color,
+			ClickAction:  n.ClickAction,
+			BodyLocKey:   n.BodyLocKey,
+			BodyLocArgs:  copyStrings(n.BodyLocArgs),
+			TitleLocKey:  n.TitleLocKey,
+			TitleLocArgs: copyStrings(n.TitleLocArgs),
+		}
+
+		if n.Color != "" {
+			if _, err := ParseColor(n.Color); err != nil {
+				return nil, fmt.Errorf("notification.color: %w", err)
+			}
+		}
+
+		if an.ChannelId != "" || an.Icon != "" || an.Sound != "" || an.Tag != "" || an.Color != "" ||
+			an.ClickAction != "" || an.BodyLocKey != "" || an.TitleLocKey != "" {
+			android.Notification = &an
+		}
+
+		if n.Subtitle != "" {
+			report.add("notification.subtitle", "iOS only, not supported by Android config")
+		}
+
+		if n.Badge != "" {
+			report.add("notification.badge", "iOS only, use android.notification.notification_count")
+		}
+	}
+
+	if android.CollapseKey != "" || android.RestrictedPackageName != "" || android.Priority != "" ||
+		android.Ttl != nil || android.Notification != nil {
+		msg.Android = &android
+	}
+
+	return &msg, nil
+}
+
+func legacyDataValue(v interface{}) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
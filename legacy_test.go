@@ -0,0 +1,81 @@
+package fcm
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConvertLegacy", func() {
+	It("should convert payload for every registration id", func() {
+		var legacy LegacyMessage
+		Ω(json.Unmarshal([]byte(`{
+			"registration_ids": ["a", "b"],
+			"collapse_key": "news",
+			"priority": "high",
+			"time_to_live": 600,
+			"content_available": true,
+			"data": {"id": "1", "count": 2},
+			"notification": {"title": "Hi", "body": "There", "android_channel_id": "news", "badge": "1"}
+		}`), &legacy)).Should(Succeed())
+
+		messages, report, err := ConvertLegacy(&legacy)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(messages).Should(HaveLen(2))
+
+		Ω(messages[0]).Should(Equal(&Message{
+			Token:        "a",
+			Data:         map[string]string{"id": "1", "count": "2"},
+			Notification: &Notification{Title: "Hi", Body: "There"},
+			Android: &AndroidConfig{
+				CollapseKey:  "news",
+				Priority:     AndroidMessagePriorityHigh,
+				Ttl:          NewDuration(10 * time.Minute),
+				Notification: &AndroidNotification{ChannelId: "news"},
+			},
+		}))
+		Ω(messages[1].Token).Should(Equal("b"))
+
+		messages[1].Data["id"] = "2"
+		Ω(messages[0].Data["id"]).Should(Equal("1"))
+
+		var fields []string
+		for _, issue := range report.Issues {
+			fields = append(fields, issue.Field)
+		}
+
+		Ω(fields).Should(Equal([]string{"content_available", "data.count", "notification.badge"}))
+	})
+
+	It("should convert topic and condition targets", func() {
+		messages, _, err := ConvertLegacy(&LegacyMessage{To: "/topics/news"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(messages).Should(Equal([]*Message{{Topic: "news"}}))
+
+		messages, _, err = ConvertLegacy(&LegacyMessage{Condition: "'a' in topics"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(messages).Should(Equal([]*Message{{Condition: "'a' in topics"}}))
+	})
+
+	It("should fail on invalid payload", func() {
+		_, _, err := ConvertLegacy(&LegacyMessage{})
+		Ω(errors.Is(err, ErrInvalidTarget)).Should(BeTrue())
+
+		_, _, err = ConvertLegacy(&LegacyMessage{To: "a", RegistrationIDs: []string{"b"}})
+		Ω(errors.Is(err, ErrMultipleTargets)).Should(BeTrue())
+
+		_, _, err = ConvertLegacy(&LegacyMessage{To: "a", Priority: "urgent"})
+		Ω(errors.Is(err, ErrInvalidEnum)).Should(BeTrue())
+
+		ttl := -1
+		_, _, err = ConvertLegacy(&LegacyMessage{To: "a", TimeToLive: &ttl})
+		Ω(errors.Is(err, ErrInvalidDuration)).Should(BeTrue())
+
+		_, _, err = ConvertLegacy(&LegacyMessage{To: "a", Notification: &LegacyNotification{Color: "red"}})
+		Ω(errors.Is(err, ErrInvalidColor)).Should(BeTrue())
+		Ω(err.Error()).Should(HavePrefix("notification.color: "))
+	})
+})
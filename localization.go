@@ -0,0 +1,340 @@
+package fcm
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ErrMissingTranslation occurs if catalog has no entry for the key in any of fallback locales.
+var ErrMissingTranslation = errors.New("translation is missing")
+
+// Plural categories of CLDR plural rules used as keys of plural entries.
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// Catalog holds translated strings per locale. Entries are either plain strings
+// or maps of plural forms, e.g. in YAML:
+//
+//	order.title: "Order %1$s"
+//	order.body:
+//	  one: "You have %1$s new item"
+//	  other: "You have %1$s new items"
+//
+// Strings are formatted like Android string resources: "%s" and "%d" take the
+// next argument, "%1$s" takes the argument by position.
+type Catalog struct {
+	locales map[string]map[string]catalogEntry
+}
+
+type catalogEntry struct {
+	text   string
+	plural map[string]string
+}
+
+func (e *catalogEntry) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&e.text); err == nil {
+		return nil
+	}
+
+	return unmarshal(&e.plural)
+}
+
+// NewCatalog returns an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{locales: make(map[string]map[string]catalogEntry)}
+}
+
+// Parse adds entries of the locale from JSON or YAML document.
+func (c *Catalog) Parse(locale string, data []byte) error {
+	var entries map[string]catalogEntry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse %q catalog: %w", locale, err)
+	}
+
+	locale = normalizeLocale(locale)
+	if c.locales[locale] == nil {
+		c.locales[locale] = make(map[string]catalogEntry, len(entries))
+	}
+
+	for key, entry := range entries {
+		c.locales[locale][key] = entry
+	}
+
+	return nil
+}
+
+// LoadFile adds entries from JSON or YAML file, the locale is taken
+// from the file name, e.g. "i18n/pt-BR.yaml".
+func (c *Catalog) LoadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read catalog: %w", err)
+	}
+
+	base := filepath.Base(path)
+	return c.Parse(strings.TrimSuffix(base, filepath.Ext(base)), data)
+}
+
+// LoadDir adds entries from all .json, .yaml and .yml files of the directory.
+func (c *Catalog) LoadDir(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read catalog directory: %w", err)
+	}
+
+	for _, f := range files {
+		switch filepath.Ext(f.Name()) {
+		case ".json", ".yaml", ".yml":
+			if err := c.LoadFile(filepath.Join(dir, f.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// LocalizedText references catalog entries of the notification title and body.
+type LocalizedText struct {
+	TitleKey  string
+	TitleArgs []string
+	BodyKey   string
+	BodyArgs  []string
+
+	// Count selects the form of plural entries.
+	Count int
+
+	// ClientSide emits loc keys and args to be resolved by the app on the device
+	// instead of the translated texts. Use it for apps having the strings bundled.
+	ClientSide bool
+}
+
+// Localizer fills notification texts from the catalog. Translations are looked up
+// in the fallback chain: the full locale ("pt-BR"), its language ("pt")
+// and the default locale.
+type Localizer struct {
+	catalog       *Catalog
+	defaultLocale string
+}
+
+// NewLocalizer returns Localizer using the catalog.
+func NewLocalizer(catalog *Catalog, defaultLocale string) *Localizer {
+	return &Localizer{
+		catalog:       catalog,
+		defaultLocale: normalizeLocale(defaultLocale),
+	}
+}
+
+// Localize sets the notification title and body of the message translated
+// to the locale, or Android loc keys if text is resolved on the client side.
+func (l *Localizer) Localize(msg *Message, locale string, text LocalizedText) error {
+	if text.ClientSide {
+		if msg.Android == nil {
+			msg.Android = &AndroidConfig{}
+		}
+
+		if msg.Android.Notification == nil {
+			msg.Android.Notification = &AndroidNotification{}
+		}
+
+		n := msg.Android.Notification
+		n.TitleLocKey, n.TitleLocArgs = text.TitleKey, copyStrings(text.TitleArgs)
+		n.BodyLocKey, n.BodyLocArgs = text.BodyKey, copyStrings(text.BodyArgs)
+		return nil
+	}
+
+	var title, body string
+	var err error
+	if text.TitleKey != "" {
+		if title, err = l.Translate(locale, text.TitleKey, text.Count, text.TitleArgs...); err != nil {
+			return err
+		}
+	}
+
+	if text.BodyKey != "" {
+		if body, err = l.Translate(locale, text.BodyKey, text.Count, text.BodyArgs...); err != nil {
+			return err
+		}
+	}
+
+	if msg.Notification == nil {
+		msg.Notification = &Notification{}
+	}
+
+	mergeString(&msg.Notification.Title, title)
+	mergeString(&msg.Notification.Body, body)
+	return nil
+}
+
+// Translate returns the catalog entry translated to the locale and formatted with args.
+// The count selects the form of plural entries.
+func (l *Localizer) Translate(locale, key string, count int, args ...string) (string, error) {
+	for _, loc := range l.fallbackChain(locale) {
+		entry, ok := l.catalog.locales[loc][key]
+		if !ok {
+			continue
+		}
+
+		text := entry.text
+		if entry.plural != nil {
+			text, ok = entry.plural[pluralCategory(loc, count)]
+			if !ok {
+				text = entry.plural[PluralOther]
+			}
+		}
+
+		return formatLocalized(text, args), nil
+	}
+
+	return "", fmt.Errorf("%w: %q for %q locale", ErrMissingTranslation, key, locale)
+}
+
+func (l *Localizer) fallbackChain(locale string) []string {
+	locale = normalizeLocale(locale)
+	chain := []string{locale}
+	if idx := strings.IndexByte(locale, '-'); idx > 0 {
+		chain = append(chain, locale[:idx])
+	}
+
+	if l.defaultLocale != "" && l.defaultLocale != locale {
+		chain = append(chain, l.defaultLocale)
+	}
+
+	return chain
+}
+
+// normalizeLocale converts locale to lower case with dash separator, e.g. "pt_BR" to "pt-br".
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(locale, "_", "-", -1))
+}
+
+// formatLocalized substitutes Android style format specifiers with args.
+func formatLocalized(text string, args []string) string {
+	if !strings.Contains(text, "%") {
+		return text
+	}
+
+	var sb strings.Builder
+	next := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '%' || i+1 >= len(text) {
+			sb.WriteByte(text[i])
+			continue
+		}
+
+		if text[i+1] == '%' {
+			sb.WriteByte('%')
+			i++
+			continue
+		}
+
+		// positional specifier, e.g. "%2$s"
+		j := i + 1
+		for j < len(text) && text[j] >= '0' && text[j] <= '9' {
+			j++
+		}
+
+		idx, sequential := next, true
+		if j > i+1 && j+1 < len(text) && text[j] == '$' {
+			pos, _ := strconv.Atoi(text[i+1 : j])
+			idx, sequential = pos-1, false
+			j++
+		} else {
+			j = i + 1
+		}
+
+		// a literal percent sign like in "100% sure" doesn't consume an argument
+		if j >= len(text) || (text[j] != 's' && text[j] != 'd') || idx < 0 || idx >= len(args) {
+			sb.WriteByte(text[i])
+			continue
+		}
+
+		if sequential {
+			next++
+		}
+
+		sb.WriteString(args[idx])
+		i = j
+	}
+
+	return sb.String()
+}
+
+// pluralCategory returns CLDR plural category of the integer count for the locale.
+// Rules of the most common languages are supported, others use English rules.
+func pluralCategory(locale string, n int) string {
+	lang := locale
+	if idx := strings.IndexByte(locale, '-'); idx > 0 {
+		lang = locale[:idx]
+	}
+
+	if n < 0 {
+		n = -n
+	}
+
+	mod10, mod100 := n%10, n%100
+	switch lang {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms":
+		return PluralOther
+	case "fr", "pt", "hi":
+		if n == 0 || n == 1 {
+			return PluralOne
+		}
+	case "ru", "uk", "be":
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+	case "pl":
+		switch {
+		case n == 1:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return PluralOne
+		case n >= 2 && n <= 4:
+			return PluralFew
+		}
+	case "ar":
+		switch {
+		case n == 0:
+			return PluralZero
+		case n == 1:
+			return PluralOne
+		case n == 2:
+			return PluralTwo
+		case mod100 >= 3 && mod100 <= 10:
+			return PluralFew
+		case mod100 >= 11:
+			return PluralMany
+		}
+	default:
+		if n == 1 {
+			return PluralOne
+		}
+	}
+
+	return PluralOther
+}
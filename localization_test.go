@@ -0,0 +1,121 @@
+package fcm
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Localizer", func() {
+	var (
+		dir       string
+		localizer *Localizer
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "fcm-i18n")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		files := map[string]string{
+			"en.json": `{
+				"order.title": "Order %1$s",
+				"order.body": {"one": "You have %s new item", "other": "You have %s new items"},
+				"promo.body": "50%% off"
+			}`,
+			"ru.yaml": `
+order.title: "Заказ %1$s"
+order.body:
+  one: "У вас %s новый товар"
+  few: "У вас %s новых товара"
+  many: "У вас %s новых товаров"
+`,
+			"pt-BR.yml": `order.title: "Pedido %1$s"`,
+			"README.md": `not a catalog`,
+		}
+
+		for name, content := range files {
+			Ω(ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600)).Should(Succeed())
+		}
+
+		catalog := NewCatalog()
+		Ω(catalog.LoadDir(dir)).Should(Succeed())
+		localizer = NewLocalizer(catalog, "en")
+	})
+
+	AfterEach(func() {
+		Ω(os.RemoveAll(dir)).Should(Succeed())
+	})
+
+	table.DescribeTable("Translate func",
+		func(locale, key string, count int, expected string) {
+			text, err := localizer.Translate(locale, key, count, "42")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(text).Should(Equal(expected))
+		},
+		table.Entry("default locale", "en", "order.title", 0, "Order 42"),
+		table.Entry("english singular", "en-US", "order.body", 1, "You have 42 new item"),
+		table.Entry("english plural", "en", "order.body", 5, "You have 42 new items"),
+		table.Entry("escaped percent", "en", "promo.body", 0, "50% off"),
+		table.Entry("russian few", "ru", "order.body", 3, "У вас 42 новых товара"),
+		table.Entry("russian many", "ru_RU", "order.body", 11, "У вас 42 новых товаров"),
+		table.Entry("russian one", "ru", "order.body", 21, "У вас 42 новый товар"),
+		table.Entry("region locale", "pt_BR", "order.title", 0, "Pedido 42"),
+		table.Entry("fallback to default", "pt-BR", "order.body", 2, "You have 42 new items"),
+		table.Entry("unknown locale", "de", "order.title", 0, "Order 42"),
+	)
+
+	It("should fail on missing key", func() {
+		_, err := localizer.Translate("ru", "unknown", 0)
+		Ω(errors.Is(err, ErrMissingTranslation)).Should(BeTrue())
+	})
+
+	It("should fill notification texts", func() {
+		msg := &Message{Token: "token"}
+		Ω(localizer.Localize(msg, "ru", LocalizedText{
+			TitleKey:  "order.title",
+			TitleArgs: []string{"#7"},
+			BodyKey:   "order.body",
+			BodyArgs:  []string{"2"},
+			Count:     2,
+		})).Should(Succeed())
+
+		Ω(msg.Notification).Should(Equal(&Notification{Title: "Заказ #7", Body: "У вас 2 новых товара"}))
+	})
+
+	It("should emit loc keys for client side localization", func() {
+		msg := &Message{Token: "token"}
+		Ω(localizer.Localize(msg, "ru", LocalizedText{
+			TitleKey:   "order.title",
+			TitleArgs:  []string{"#7"},
+			BodyKey:    "order.body",
+			ClientSide: true,
+		})).Should(Succeed())
+
+		Ω(msg.Notification).Should(BeNil())
+		Ω(msg.Android.Notification).Should(Equal(&AndroidNotification{
+			TitleLocKey:  "order.title",
+			TitleLocArgs: []string{"#7"},
+			BodyLocKey:   "order.body",
+		}))
+	})
+})
+
+var _ = Describe("Localized text formatting", func() {
+	table.DescribeTable("formatLocalized func",
+		func(text string, args []string, expected string) {
+			Ω(formatLocalized(text, args)).Should(Equal(expected))
+		},
+		table.Entry("sequential", "%s of %d", []string{"1", "2"}, "1 of 2"),
+		table.Entry("positional", "%2$s before %1$s", []string{"a", "b"}, "b before a"),
+		table.Entry("escaped percent", "%d%%", []string{"5"}, "5%"),
+		table.Entry("literal percent", "100% sure, %s of %s", []string{"a", "b"}, "100% sure, a of b"),
+		table.Entry("trailing percent", "%s at 100%", []string{"a"}, "a at 100%"),
+		table.Entry("missing argument", "%s and %s", []string{"a"}, "a and %s"),
+	)
+})
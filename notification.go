@@ -2,7 +2,6 @@ package fcm
 
 import (
 	"errors"
-	"strings"
 )
 
 var (
@@ -30,17 +29,16 @@ type Message struct {
 }
 
 // Validate returns an error if the message is not well-formed.
+// All found violations are returned as ValidationErrors, so the error is never
+// equal to a sentinel like ErrInvalidTarget, use errors.Is to check it.
 func (msg *Message) Validate() error {
 	if msg == nil {
 		return ErrInvalidMessage
 	}
 
-	// validate target identifier: `to` or `condition`, or `registration_ids`
-	opCnt := strings.Count(msg.Condition, "&&") + strings.Count(msg.Condition, "||")
-	if msg.Token == "" && (msg.Condition == "" || opCnt > 2) && len(msg.Topic) == 0 {
-		return ErrInvalidTarget
-	}
-	return nil
+	var v validator
+	v.message(msg)
+	return v.err()
 }
 
 // See https://firebase.google.com/docs/reference/fcm/rest/v1/projects.messages#Notification
@@ -54,7 +52,7 @@ type Notification struct {
 type AndroidConfig struct {
 	CollapseKey           string                 `json:"collapse_key,omitempty"`
 	Priority              AndroidMessagePriority `json:"priority,omitempty"`
-	Ttl                   string                 `json:"ttl,omitempty"`
+	Ttl                   *Duration              `json:"ttl,omitempty"`
 	RestrictedPackageName string                 `json:"restricted_package_name,omitempty"`
 	Data                  map[string]string      `json:"data,omitempty"`
 	Notification          *AndroidNotification   `json:"notification,omitempty"`
@@ -86,24 +84,29 @@ type AndroidNotification struct {
 	DefaultSound          bool                 `json:"default_sound,omitempty"`
 	DefaultVibrateTimings bool                 `json:"default_vibrate_timings,omitempty"`
 	DefaultLightSettings  bool                 `json:"default_light_settings,omitempty"`
-	VibrateTimings        []string             `json:"vibrate_timings,omitempty"`
+	VibrateTimings        []Duration           `json:"vibrate_timings,omitempty"`
 	Visibility            Visibility           `json:"visibility,omitempty"`
 	NotificationCount     int                  `json:"notification_count,omitempty"`
 	LightSettings         *LightSettings       `json:"light_settings,omitempty"`
 	Image                 string               `json:"image,omitempty"`
 }
 
+// Color represents a color in the RGBA color space, components are in [0, 1] range.
+// Color components are always marshalled, so black is sent as it is. Zero alpha
+// is omitted which FCM treats as the opaque color, the same as alpha 1.
+// AndroidNotification.Color uses #rrggbb format instead, see ParseColor and Color.Hex.
+// See https://firebase.google.com/docs/reference/fcm/rest/v1/projects.messages#Color
 type Color struct {
-	Red   float32 `json:"red,omitempty"`
-	Green float32 `json:"green,omitempty"`
-	Blue  float32 `json:"blue,omitempty"`
+	Red   float32 `json:"red"`
+	Green float32 `json:"green"`
+	Blue  float32 `json:"blue"`
 	Alpha float32 `json:"alpha,omitempty"`
 }
 
 type LightSettings struct {
-	Color            Color  `json:"color"`
-	LightOnDuration  string `json:"light_on_duration,omitempty"`
-	LightOffDuration string `json:"light_off_duration,omitempty"`
+	Color            Color     `json:"color"`
+	LightOnDuration  *Duration `json:"light_on_duration,omitempty"`
+	LightOffDuration *Duration `json:"light_off_duration,omitempty"`
 }
 
 type AndroidMessagePriority string
@@ -120,9 +123,10 @@ const (
 	NotificationPriorityHigh        NotificationPriority = "PRIORITY_HIGH"
 	NotificationPriorityMax         NotificationPriority = "PRIORITY_MAX"
 
-	VisibilityPrivate Visibility = "PRIVATE"
-	VisibilityPublic  Visibility = "PUBLIC"
-	VisibilitySecret  Visibility = "SECRET"
+	VisibilityUnspecified Visibility = "VISIBILITY_UNSPECIFIED"
+	VisibilityPrivate     Visibility = "PRIVATE"
+	VisibilityPublic      Visibility = "PUBLIC"
+	VisibilitySecret      Visibility = "SECRET"
 
 	AndroidMessagePriorityNormal AndroidMessagePriority = "NORMAL"
 	AndroidMessagePriorityHigh   AndroidMessagePriority = "HIGH"
@@ -228,7 +228,15 @@ func easyjson9806e1DecodeGithubComHumansNetFcm2(in *jlexer.Lexer, out *AndroidCo
 		case "priority":
 			out.Priority = AndroidMessagePriority(in.String())
 		case "ttl":
-			out.Ttl = string(in.String())
+			if in.IsNull() {
+				in.Skip()
+				out.Ttl = nil
+			} else {
+				if out.Ttl == nil {
+					out.Ttl = new(Duration)
+				}
+				(*out.Ttl).UnmarshalEasyJSON(in)
+			}
 		case "restricted_package_name":
 			out.RestrictedPackageName = string(in.String())
 		case "data":
@@ -303,7 +311,7 @@ func easyjson9806e1EncodeGithubComHumansNetFcm2(out *jwriter.Writer, in AndroidC
 		}
 		out.String(string(in.Priority))
 	}
-	if in.Ttl != "" {
+	if in.Ttl != nil {
 		const prefix string = ",\"ttl\":"
 		if first {
 			first = false
@@ -311,7 +319,7 @@ func easyjson9806e1EncodeGithubComHumansNetFcm2(out *jwriter.Writer, in AndroidC
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Ttl))
+		(*in.Ttl).MarshalEasyJSON(out)
 	}
 	if in.RestrictedPackageName != "" {
 		const prefix string = ",\"restricted_package_name\":"
@@ -531,16 +539,16 @@ func easyjson9806e1DecodeGithubComHumansNetFcm3(in *jlexer.Lexer, out *AndroidNo
 				in.Delim('[')
 				if out.VibrateTimings == nil {
 					if !in.IsDelim(']') {
-						out.VibrateTimings = make([]string, 0, 4)
+						out.VibrateTimings = make([]Duration, 0, 8)
 					} else {
-						out.VibrateTimings = []string{}
+						out.VibrateTimings = []Duration{}
 					}
 				} else {
 					out.VibrateTimings = (out.VibrateTimings)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v7 string
-					v7 = string(in.String())
+					var v7 Duration
+					(v7).UnmarshalEasyJSON(in)
 					out.VibrateTimings = append(out.VibrateTimings, v7)
 					in.WantComma()
 				}
@@ -804,7 +812,7 @@ func easyjson9806e1EncodeGithubComHumansNetFcm3(out *jwriter.Writer, in AndroidN
 				if v12 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v13))
+				(v13).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -873,9 +881,25 @@ func easyjson9806e1DecodeGithubComHumansNetFcm5(in *jlexer.Lexer, out *LightSett
 		case "color":
 			easyjson9806e1DecodeGithubComHumansNetFcm6(in, &out.Color)
 		case "light_on_duration":
-			out.LightOnDuration = string(in.String())
+			if in.IsNull() {
+				in.Skip()
+				out.LightOnDuration = nil
+			} else {
+				if out.LightOnDuration == nil {
+					out.LightOnDuration = new(Duration)
+				}
+				(*out.LightOnDuration).UnmarshalEasyJSON(in)
+			}
 		case "light_off_duration":
-			out.LightOffDuration = string(in.String())
+			if in.IsNull() {
+				in.Skip()
+				out.LightOffDuration = nil
+			} else {
+				if out.LightOffDuration == nil {
+					out.LightOffDuration = new(Duration)
+				}
+				(*out.LightOffDuration).UnmarshalEasyJSON(in)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -895,15 +919,15 @@ func easyjson9806e1EncodeGithubComHumansNetFcm5(out *jwriter.Writer, in LightSet
 		out.RawString(prefix[1:])
 		easyjson9806e1EncodeGithubComHumansNetFcm6(out, in.Color)
 	}
-	if in.LightOnDuration != "" {
+	if in.LightOnDuration != nil {
 		const prefix string = ",\"light_on_duration\":"
 		out.RawString(prefix)
-		out.String(string(in.LightOnDuration))
+		(*in.LightOnDuration).MarshalEasyJSON(out)
 	}
-	if in.LightOffDuration != "" {
+	if in.LightOffDuration != nil {
 		const prefix string = ",\"light_off_duration\":"
 		out.RawString(prefix)
-		out.String(string(in.LightOffDuration))
+		(*in.LightOffDuration).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
@@ -948,40 +972,24 @@ func easyjson9806e1EncodeGithubComHumansNetFcm6(out *jwriter.Writer, in Color) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Red != 0 {
+	{
 		const prefix string = ",\"red\":"
-		first = false
 		out.RawString(prefix[1:])
 		out.Float32(float32(in.Red))
 	}
-	if in.Green != 0 {
+	{
 		const prefix string = ",\"green\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
+		out.RawString(prefix)
 		out.Float32(float32(in.Green))
 	}
-	if in.Blue != 0 {
+	{
 		const prefix string = ",\"blue\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
+		out.RawString(prefix)
 		out.Float32(float32(in.Blue))
 	}
 	if in.Alpha != 0 {
 		const prefix string = ",\"alpha\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
+		out.RawString(prefix)
 		out.Float32(float32(in.Alpha))
 	}
 	out.RawByte('}')
@@ -35,10 +35,35 @@ func WithEndpoint(endpoint string) Option {
 	}
 }
 
+// WithIIDEndpoint returns Option to configure Instance ID API endpoint used to manage topic subscriptions.
+func WithIIDEndpoint(endpoint string) Option {
+	return func(c *SimpleClient) error {
+		urlCfg, err := parseEndpoint(endpoint)
+		if err != nil {
+			return err
+		}
+
+		c.iidURL = urlCfg
+		return nil
+	}
+}
+
 // WithHTTPClient returns Option to configure HTTP Client.
+// The client could be shared, so it is not closed by SimpleClient.Close.
 func WithHTTPClient(httpClient FastHTTPDoer) Option {
 	return func(c *SimpleClient) error {
 		c.client = httpClient
+		c.ownsClient = false
+		return nil
+	}
+}
+
+// WithOwnedHTTPClient returns Option to configure HTTP Client used only by this SimpleClient,
+// so SimpleClient.Close closes its idle connections if it has CloseIdleConnections method.
+func WithOwnedHTTPClient(httpClient FastHTTPDoer) Option {
+	return func(c *SimpleClient) error {
+		c.client = httpClient
+		c.ownsClient = true
 		return nil
 	}
 }
@@ -62,10 +87,53 @@ func WithCredentialsData(bb []byte) Option {
 			return fmt.Errorf("failed to create token source from json: %w", err)
 		}
 
+		iidCreds, err := google.CredentialsFromJSON(context.Background(), bb, firebaseMessagingScope)
+		if err != nil {
+			return fmt.Errorf("failed to load credentials from json: %w", err)
+		}
+
 		path := fmt.Sprintf("/v1/projects/%s/messages:send", creds.ProjectID)
 		c.sendPath = []byte(path)
 		c.tokenSource = tokenSource
+		c.iidTokenSource = iidCreds.TokenSource
+
+		return nil
+	}
+}
 
+// WithInvalidTokenHandler returns Option to configure the handler called when the send
+// fails because the registration token is stale: the reason is ErrUnregistered,
+// ErrInvalidToken or ErrSenderIDMismatch. The token should not be used anymore.
+func WithInvalidTokenHandler(fn func(token string, reason error)) Option {
+	return func(c *SimpleClient) error {
+		c.invalidTokenHandler = fn
+		return nil
+	}
+}
+
+// WithTokenRegistry returns Option to configure the registry tracking
+// results of sends to registration tokens.
+func WithTokenRegistry(r TokenRegistry) Option {
+	return func(c *SimpleClient) error {
+		c.tokenRegistry = r
+		return nil
+	}
+}
+
+// WithClock returns Option to configure the clock used to timestamp send results
+// reported to the token registry, by default RealClock is used.
+func WithClock(clock Clock) Option {
+	return func(c *SimpleClient) error {
+		c.clock = clock
+		return nil
+	}
+}
+
+// WithRetryPolicy returns Option to configure retries of sends failed with temporary errors,
+// by default sends are not retried. The policy can be overridden per send with WithSendRetry.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *SimpleClient) error {
+		c.retry = policy
 		return nil
 	}
 }
@@ -94,6 +162,12 @@ const (
 	// this constant value are used as audience value for the auth token
 	// be careful in in case of changes
 	DefaultEndpoint = "https://fcm.googleapis.com/"
+
+	// DefaultIIDEndpoint contains endpoint URL of Instance ID service.
+	DefaultIIDEndpoint = "https://iid.googleapis.com/"
+
+	// firebaseMessagingScope is OAuth2 scope of access tokens for Instance ID service.
+	firebaseMessagingScope = "https://www.googleapis.com/auth/firebase.messaging"
 )
 
 type NoopTokenSource struct{}
@@ -0,0 +1,318 @@
+package fcm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Outbox durably enqueues messages to the OutboxStore and sends them with
+// a pool of workers, retrying failed sends with a backoff. Entries left pending
+// when the process stops are sent after Run is called again, so every message
+// is delivered at least once.
+type Outbox struct {
+	client Client
+	store  OutboxStore
+
+	workers      int
+	maxAttempts  int
+	backoff      func(attempt int) time.Duration
+	pollInterval time.Duration
+	retention    time.Duration
+	now          func() time.Time
+
+	wake     chan struct{}
+	mu       sync.Mutex
+	inflight map[string]struct{}
+}
+
+// OutboxOption configures Outbox with defined option.
+type OutboxOption func(*Outbox)
+
+// WithOutboxWorkers returns OutboxOption to configure number of concurrent sends.
+func WithOutboxWorkers(n int) OutboxOption {
+	return func(o *Outbox) {
+		o.workers = n
+	}
+}
+
+// WithOutboxMaxAttempts returns OutboxOption to configure number of send attempts
+// after which the entry is marked as failed.
+func WithOutboxMaxAttempts(n int) OutboxOption {
+	return func(o *Outbox) {
+		o.maxAttempts = n
+	}
+}
+
+// WithOutboxBackoff returns OutboxOption to configure delay before the next attempt.
+func WithOutboxBackoff(backoff func(attempt int) time.Duration) OutboxOption {
+	return func(o *Outbox) {
+		o.backoff = backoff
+	}
+}
+
+// WithOutboxPollInterval returns OutboxOption to configure how often the store
+// is checked for entries due to retry.
+func WithOutboxPollInterval(d time.Duration) OutboxOption {
+	return func(o *Outbox) {
+		o.pollInterval = d
+	}
+}
+
+// WithOutboxRetention returns OutboxOption to configure how long sent and failed
+// entries are kept in the store, so their state could be checked by Entry.
+// They are pruned by Run, zero value disables pruning.
+func WithOutboxRetention(d time.Duration) OutboxOption {
+	return func(o *Outbox) {
+		o.retention = d
+	}
+}
+
+// NewOutbox creates Outbox which sends messages enqueued to the store using the client.
+func NewOutbox(client Client, store OutboxStore, opts ...OutboxOption) *Outbox {
+	o := Outbox{
+		client:       client,
+		store:        store,
+		workers:      4,
+		maxAttempts:  5,
+		backoff:      ExponentialBackoff(time.Second, 5*time.Minute),
+		pollInterval: time.Second,
+		retention:    24 * time.Hour,
+		now:          time.Now,
+		wake:         make(chan struct{}, 1),
+		inflight:     make(map[string]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &o
+}
+
+// ExponentialBackoff returns backoff doubling the delay with every attempt
+// starting from base and limited by max.
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+
+		if d > max {
+			d = max
+		}
+
+		return d
+	}
+}
+
+// Enqueue validates and stores the message to be sent by workers.
+// It returns ID of the outbox entry.
+func (o *Outbox) Enqueue(msg *Message) (string, error) {
+	if err := msg.Validate(); err != nil {
+		return "", fmt.Errorf("invalid message: %w", err)
+	}
+
+	id, err := newEntryID()
+	if err != nil {
+		return "", err
+	}
+
+	now := o.now()
+	entry := OutboxEntry{
+		ID:            id,
+		Message:       msg.Clone(),
+		State:         OutboxPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		NextAttemptAt: now,
+	}
+
+	if err := o.store.Put(&entry); err != nil {
+		return "", fmt.Errorf("failed to enqueue message: %w", err)
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+
+	return id, nil
+}
+
+// Entry returns the outbox entry by ID to check its delivery state.
+func (o *Outbox) Entry(id string) (*OutboxEntry, error) {
+	return o.store.Get(id)
+}
+
+// Run sends pending entries until the context is done, then waits
+// for the in-flight sends to finish. Completed entries are pruned after
+// the retention period. It returns the first store error or the context error.
+func (o *Outbox) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan *OutboxEntry)
+	errCh := make(chan error, o.workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < o.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range jobs {
+				if err := o.process(ctx, entry); err != nil {
+					errCh <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(o.pollInterval)
+	defer ticker.Stop()
+
+	var (
+		err       error
+		nextPrune time.Time
+	)
+loop:
+	for {
+		if err = o.dispatch(ctx, jobs); err != nil {
+			break
+		}
+
+		if now := o.now(); o.retention > 0 && !now.Before(nextPrune) {
+			if _, err = o.store.Prune(now.Add(-o.retention)); err != nil {
+				err = fmt.Errorf("failed to prune outbox entries: %w", err)
+				break
+			}
+
+			// so completed entries are kept for at most 1.5 times the retention
+			nextPrune = now.Add(o.retention / 2)
+		}
+
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	select {
+	case workerErr := <-errCh:
+		return workerErr
+	default:
+	}
+
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// dispatch passes due entries which are not sent yet to the workers.
+func (o *Outbox) dispatch(ctx context.Context, jobs chan<- *OutboxEntry) error {
+	for {
+		entries, err := o.store.Pending(o.now(), o.workers*2)
+		if err != nil {
+			return fmt.Errorf("failed to get pending entries: %w", err)
+		}
+
+		dispatched := 0
+		for _, entry := range entries {
+			if !o.acquire(entry.ID) {
+				continue
+			}
+
+			select {
+			case jobs <- entry:
+				dispatched++
+			case <-ctx.Done():
+				o.release(entry.ID)
+				return ctx.Err()
+			}
+		}
+
+		if dispatched == 0 {
+			return nil
+		}
+	}
+}
+
+// process sends the entry and saves the result.
+func (o *Outbox) process(ctx context.Context, entry *OutboxEntry) error {
+	defer o.release(entry.ID)
+
+	err := o.client.Send(ctx, entry.Message)
+	if err != nil && ctx.Err() != nil {
+		// interrupted by shutdown, the entry is kept pending to be resumed
+		return nil
+	}
+
+	entry.Attempts++
+	entry.UpdatedAt = o.now()
+
+	switch {
+	case err == nil:
+		entry.State = OutboxSent
+		entry.LastError = ""
+	case isPermanentSendError(err) || entry.Attempts >= o.maxAttempts:
+		entry.State = OutboxFailed
+		entry.LastError = err.Error()
+	default:
+		entry.LastError = err.Error()
+		entry.NextAttemptAt = entry.UpdatedAt.Add(o.backoff(entry.Attempts))
+	}
+
+	if err := o.store.Put(entry); err != nil {
+		return fmt.Errorf("failed to save outbox entry: %w", err)
+	}
+
+	return nil
+}
+
+func (o *Outbox) acquire(id string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.inflight[id]; ok {
+		return false
+	}
+
+	o.inflight[id] = struct{}{}
+	return true
+}
+
+func (o *Outbox) release(id string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.inflight, id)
+}
+
+// isPermanentSendError reports whether the send will fail on retry as well.
+func isPermanentSendError(err error) bool {
+	var validationErrs ValidationErrors
+	return IsInvalidTokenError(err) || errors.Is(err, ErrInvalidMessage) || errors.As(err, &validationErrs)
+}
+
+func newEntryID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate entry id: %w", err)
+	}
+
+	return hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,316 @@
+package fcm
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrEntryNotFound occurs if store has no entry with requested ID.
+var ErrEntryNotFound = errors.New("entry is not found")
+
+// OutboxState is a delivery state of the outbox entry.
+type OutboxState string
+
+const (
+	OutboxPending OutboxState = "PENDING"
+	OutboxSent    OutboxState = "SENT"
+	OutboxFailed  OutboxState = "FAILED"
+)
+
+// OutboxEntry is a message enqueued to the Outbox with its delivery state.
+type OutboxEntry struct {
+	ID            string      `json:"id"`
+	Message       *Message    `json:"message"`
+	State         OutboxState `json:"state"`
+	Attempts      int         `json:"attempts"`
+	LastError     string      `json:"last_error,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	NextAttemptAt time.Time   `json:"next_attempt_at"`
+}
+
+// OutboxStore persists outbox entries. Implementations must be safe for concurrent use.
+// Entries passed to and returned from the store must not be shared with it.
+type OutboxStore interface {
+	// Put saves the entry, replacing an existing entry with the same ID.
+	Put(entry *OutboxEntry) error
+	// Get returns the entry by ID or ErrEntryNotFound.
+	Get(id string) (*OutboxEntry, error)
+	// Pending returns at most limit pending entries due at the time,
+	// ordered by the next attempt time.
+	Pending(now time.Time, limit int) ([]*OutboxEntry, error)
+	// Prune removes sent and failed entries updated before the time
+	// and returns number of removed entries.
+	Prune(before time.Time) (int, error)
+}
+
+var (
+	_ OutboxStore = (*MemoryOutboxStore)(nil)
+	_ OutboxStore = (*FileOutboxStore)(nil)
+)
+
+// MemoryOutboxStore keeps entries in memory, they are lost on restart.
+type MemoryOutboxStore struct {
+	mu      sync.RWMutex
+	entries map[string]OutboxEntry
+}
+
+// NewMemoryOutboxStore returns an empty in-memory store.
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{entries: make(map[string]OutboxEntry)}
+}
+
+func (s *MemoryOutboxStore) Put(entry *OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[entry.ID] = entry.clone()
+	return nil
+}
+
+func (s *MemoryOutboxStore) Get(id string) (*OutboxEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrEntryNotFound, id)
+	}
+
+	cp := entry.clone()
+	return &cp, nil
+}
+
+func (s *MemoryOutboxStore) Pending(now time.Time, limit int) ([]*OutboxEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var pending []*OutboxEntry
+	for _, entry := range s.entries {
+		if entry.State == OutboxPending && !entry.NextAttemptAt.After(now) {
+			cp := entry.clone()
+			pending = append(pending, &cp)
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].NextAttemptAt.Equal(pending[j].NextAttemptAt) {
+			return pending[i].NextAttemptAt.Before(pending[j].NextAttemptAt)
+		}
+
+		return pending[i].ID < pending[j].ID
+	})
+
+	if limit > 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+
+	return pending, nil
+}
+
+func (s *MemoryOutboxStore) Prune(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := 0
+	for id, entry := range s.entries {
+		if entry.State != OutboxPending && entry.UpdatedAt.Before(before) {
+			delete(s.entries, id)
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+// clone returns copy of the entry which doesn't share the message.
+func (e *OutboxEntry) clone() OutboxEntry {
+	cp := *e
+	cp.Message = e.Message.Clone()
+	return cp
+}
+
+// FileOutboxStore keeps entries in memory and appends every change to a local
+// file as a JSON line, so the entries survive restarts. The file is compacted
+// when the store is opened and on Prune.
+type FileOutboxStore struct {
+	*MemoryOutboxStore
+
+	path    string
+	writeMu sync.Mutex
+	file    *os.File
+	written int // lines appended since the last compaction
+}
+
+// OpenFileOutboxStore opens the store file, creating it if it doesn't exist,
+// and loads entries written before.
+func OpenFileOutboxStore(path string) (*FileOutboxStore, error) {
+	s := FileOutboxStore{MemoryOutboxStore: NewMemoryOutboxStore(), path: path}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (s *FileOutboxStore) Put(entry *OutboxEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox entry: %w", err)
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write outbox entry: %w", err)
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync outbox file: %w", err)
+	}
+
+	s.written++
+	return s.MemoryOutboxStore.Put(entry)
+}
+
+// Prune removes completed entries and compacts the file if anything is removed
+// or it has more outdated lines than entries.
+func (s *FileOutboxStore) Prune(before time.Time) (int, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	removed, _ := s.MemoryOutboxStore.Prune(before)
+
+	s.mu.RLock()
+	outdated := s.written > len(s.entries)
+	s.mu.RUnlock()
+
+	if removed == 0 && !outdated {
+		return 0, nil
+	}
+
+	return removed, s.compact()
+}
+
+// Close closes the store file.
+func (s *FileOutboxStore) Close() error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return s.file.Close()
+}
+
+// load replays the file, the last written state of each entry wins.
+func (s *FileOutboxStore) load() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to open outbox file: %w", err)
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var parseErr error
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		// only the last line could be partially written on crash
+		if parseErr != nil {
+			return parseErr
+		}
+
+		var entry OutboxEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			parseErr = fmt.Errorf("failed to parse outbox file line %d: %w", lineNum, err)
+			continue
+		}
+
+		_ = s.MemoryOutboxStore.Put(&entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read outbox file: %w", err)
+	}
+
+	return nil
+}
+
+// compact rewrites the file with the current entries only and opens it for appending.
+// It must be called with writeMu held or before the store is shared. The entries are
+// written to the temporary file renamed into place, the old file is closed only after
+// that, so it is kept open for appending on failure.
+func (s *FileOutboxStore) compact() error {
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox file: %w", err)
+	}
+
+	if err := s.rewrite(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// make the rename durable
+	if dir, err := os.Open(filepath.Dir(s.path)); err == nil {
+		_ = dir.Sync()
+		dir.Close()
+	}
+
+	// the old file is replaced, so its close error doesn't lose entries
+	if s.file != nil {
+		_ = s.file.Close()
+	}
+
+	s.file = tmp
+	s.written = 0
+	return nil
+}
+
+// rewrite writes the entries to the file and renames it to the store path.
+func (s *FileOutboxStore) rewrite(file *os.File) error {
+	s.mu.RLock()
+	entries := make([]OutboxEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	s.mu.RUnlock()
+
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			return fmt.Errorf("failed to write outbox entry: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write outbox file: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync outbox file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace outbox file: %w", err)
+	}
+
+	return nil
+}
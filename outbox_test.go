@@ -0,0 +1,237 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Outbox", func() {
+	var (
+		client *ClientMock
+		ctx    context.Context
+		cancel context.CancelFunc
+		done   chan error
+	)
+
+	entryState := func(o *Outbox, id string) func() OutboxState {
+		return func() OutboxState {
+			entry, err := o.Entry(id)
+			Ω(err).ShouldNot(HaveOccurred())
+			return entry.State
+		}
+	}
+
+	run := func(o *Outbox) {
+		done = make(chan error, 1)
+		go func() {
+			done <- o.Run(ctx)
+		}()
+	}
+
+	BeforeEach(func() {
+		client = NewClientMock(GinkgoT())
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cancel()
+		if done != nil {
+			Eventually(done).Should(Receive(Equal(context.Canceled)))
+			done = nil
+		}
+	})
+
+	It("should retry failed sends", func() {
+		var calls int32
+		client.SendMock.Set(func(_ context.Context, msg *Message, _ ...SendOption) error {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return errors.New("unavailable")
+			}
+
+			return nil
+		})
+
+		outbox := NewOutbox(client, NewMemoryOutboxStore(),
+			WithOutboxBackoff(func(int) time.Duration { return time.Millisecond }),
+			WithOutboxPollInterval(time.Millisecond))
+
+		id, err := outbox.Enqueue(&Message{Token: "token"})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		run(outbox)
+		Eventually(entryState(outbox, id)).Should(Equal(OutboxSent))
+
+		entry, err := outbox.Entry(id)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(entry.Attempts).Should(Equal(3))
+		Ω(entry.LastError).Should(BeEmpty())
+	})
+
+	It("should not retry permanent errors", func() {
+		client.SendMock.Return(ErrUnregistered)
+		outbox := NewOutbox(client, NewMemoryOutboxStore())
+
+		id, err := outbox.Enqueue(&Message{Token: "token"})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		run(outbox)
+		Eventually(entryState(outbox, id)).Should(Equal(OutboxFailed))
+		Ω(client.SendAfterCounter()).Should(BeEquivalentTo(1))
+	})
+
+	It("should reject invalid messages", func() {
+		outbox := NewOutbox(client, NewMemoryOutboxStore())
+		_, err := outbox.Enqueue(&Message{})
+		Ω(errors.Is(err, ErrInvalidTarget)).Should(BeTrue())
+	})
+
+	It("should resume pending entries from file after restart", func() {
+		dir, err := ioutil.TempDir("", "fcm-outbox")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "outbox.jsonl")
+		store, err := OpenFileOutboxStore(path)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		id, err := NewOutbox(client, store).Enqueue(&Message{Token: "token", Data: map[string]string{"k": "v"}})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(store.Close()).Should(Succeed())
+
+		store, err = OpenFileOutboxStore(path)
+		Ω(err).ShouldNot(HaveOccurred())
+		defer store.Close()
+
+		client.SendMock.Set(func(_ context.Context, msg *Message, _ ...SendOption) error {
+			Ω(msg).Should(Equal(&Message{Token: "token", Data: map[string]string{"k": "v"}}))
+			return nil
+		})
+
+		outbox := NewOutbox(client, store)
+		run(outbox)
+		Eventually(entryState(outbox, id)).Should(Equal(OutboxSent))
+	})
+
+	It("should prune completed entries after the retention", func() {
+		client.SendMock.Return(nil)
+		outbox := NewOutbox(client, NewMemoryOutboxStore(),
+			WithOutboxRetention(time.Millisecond),
+			WithOutboxPollInterval(time.Millisecond))
+
+		id, err := outbox.Enqueue(&Message{Token: "token"})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		run(outbox)
+		Eventually(func() error {
+			_, err := outbox.Entry(id)
+			return err
+		}).Should(MatchError(ErrEntryNotFound))
+	})
+})
+
+var _ = Describe("OutboxStore", func() {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	It("should not share messages with the caller", func() {
+		store := NewMemoryOutboxStore()
+		entry := OutboxEntry{ID: "1", Message: &Message{Token: "token"}, State: OutboxPending}
+		Ω(store.Put(&entry)).Should(Succeed())
+		entry.Message.Token = "changed"
+
+		stored, err := store.Get("1")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(stored.Message.Token).Should(Equal("token"))
+		stored.Message.Token = "changed"
+
+		pending, err := store.Pending(now, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(pending).Should(HaveLen(1))
+		Ω(pending[0].Message.Token).Should(Equal("token"))
+	})
+
+	It("should keep appending if compaction fails", func() {
+		dir, err := ioutil.TempDir("", "fcm-outbox")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "outbox.jsonl")
+		store, err := OpenFileOutboxStore(path)
+		Ω(err).ShouldNot(HaveOccurred())
+		defer store.Close()
+
+		sent := OutboxEntry{ID: "sent", Message: &Message{Token: "token"}, State: OutboxSent, UpdatedAt: now.Add(-time.Hour)}
+		Ω(store.Put(&sent)).Should(Succeed())
+
+		// the temporary file couldn't be created in place of the directory
+		Ω(os.Mkdir(path+".tmp", 0700)).Should(Succeed())
+		_, err = store.Prune(now)
+		Ω(err).Should(HaveOccurred())
+
+		pending := OutboxEntry{ID: "pending", Message: &Message{Token: "token"}, State: OutboxPending, UpdatedAt: now}
+		Ω(store.Put(&pending)).Should(Succeed())
+
+		Ω(os.Remove(path + ".tmp")).Should(Succeed())
+		_, err = store.Prune(now)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		reopened, err := OpenFileOutboxStore(path)
+		Ω(err).ShouldNot(HaveOccurred())
+		defer reopened.Close()
+
+		entry, err := reopened.Get("pending")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(entry.State).Should(Equal(OutboxPending))
+	})
+
+	It("should prune completed entries and compact the file", func() {
+		dir, err := ioutil.TempDir("", "fcm-outbox")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "outbox.jsonl")
+		store, err := OpenFileOutboxStore(path)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		for _, entry := range []OutboxEntry{
+			{ID: "pending", State: OutboxPending, UpdatedAt: now.Add(-time.Hour)},
+			{ID: "sent", State: OutboxSent, UpdatedAt: now.Add(-time.Hour)},
+			{ID: "failed", State: OutboxFailed, UpdatedAt: now.Add(-time.Hour)},
+			{ID: "recent", State: OutboxSent, UpdatedAt: now},
+		} {
+			entry := entry
+			entry.Message = &Message{Token: "token"}
+			Ω(store.Put(&entry)).Should(Succeed())
+		}
+
+		removed, err := store.Prune(now.Add(-time.Minute))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(removed).Should(Equal(2))
+
+		recent := OutboxEntry{ID: "recent", Message: &Message{Token: "token"}, State: OutboxFailed, UpdatedAt: now}
+		Ω(store.Put(&recent)).Should(Succeed())
+		Ω(store.Close()).Should(Succeed())
+
+		data, err := ioutil.ReadFile(path)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(strings.Count(string(data), "\n")).Should(Equal(3))
+
+		store, err = OpenFileOutboxStore(path)
+		Ω(err).ShouldNot(HaveOccurred())
+		defer store.Close()
+
+		_, err = store.Get("sent")
+		Ω(errors.Is(err, ErrEntryNotFound)).Should(BeTrue())
+		entry, err := store.Get("recent")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(entry.State).Should(Equal(OutboxFailed))
+	})
+})
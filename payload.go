@@ -0,0 +1,179 @@
+package fcm
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// MaxPayloadSize is the maximum size of the message payload in bytes accepted by FCM.
+const MaxPayloadSize = 4096
+
+// PayloadSize returns size of the message payload the way FCM counts it against
+// MaxPayloadSize: the sum of data keys and values and of non-empty notification
+// fields with their names. Targets and delivery options are not counted.
+func (msg *Message) PayloadSize() int {
+	size := 0
+	addData := func(data map[string]string) {
+		for k, v := range data {
+			size += len(k) + len(v)
+		}
+	}
+
+	addData(msg.Data)
+
+	if n := msg.Notification; n != nil {
+		size += fieldSize("title", n.Title) + fieldSize("body", n.Body) + fieldSize("image", n.Image)
+	}
+
+	if msg.Android == nil {
+		return size
+	}
+
+	addData(msg.Android.Data)
+
+	if n := msg.Android.Notification; n != nil {
+		for _, f := range []struct {
+			key, value string
+		}{
+			{"title", n.Title},
+			{"body", n.Body},
+			{"icon", n.Icon},
+			{"color", n.Color},
+			{"sound", n.Sound},
+			{"tag", n.Tag},
+			{"click_action", n.ClickAction},
+			{"body_loc_key", n.BodyLocKey},
+			{"title_loc_key", n.TitleLocKey},
+			{"channel_id", n.ChannelId},
+			{"ticker", n.Ticker},
+			{"image", n.Image},
+		} {
+			size += fieldSize(f.key, f.value)
+		}
+
+		for _, arg := range n.BodyLocArgs {
+			size += len(arg)
+		}
+
+		for _, arg := range n.TitleLocArgs {
+			size += len(arg)
+		}
+	}
+
+	return size
+}
+
+func fieldSize(key, value string) int {
+	if value == "" {
+		return 0
+	}
+
+	return len(key) + len(value)
+}
+
+// TruncatePolicy shortens notification texts so the message fits into the payload limit.
+// Bodies are shortened first, titles only if it is not enough.
+type TruncatePolicy struct {
+	// MaxSize is the payload size limit, MaxPayloadSize is used if zero.
+	MaxSize int
+	// Ellipsis is appended to the shortened text, "…" is used if empty.
+	Ellipsis string
+}
+
+// TruncatedField describes a field shortened by Message.Truncate.
+type TruncatedField struct {
+	Path         string
+	OriginalSize int
+	Size         int
+}
+
+// Truncate shortens notification texts according to the policy until the payload
+// fits into the limit, texts are cut on UTF-8 boundaries.
+// It returns the list of shortened fields, and ErrPayloadTooLarge
+// if the payload doesn't fit even without the texts, the message is kept intact then.
+func (msg *Message) Truncate(policy TruncatePolicy) ([]TruncatedField, error) {
+	if msg == nil {
+		return nil, ErrInvalidMessage
+	}
+
+	maxSize := policy.MaxSize
+	if maxSize == 0 {
+		maxSize = MaxPayloadSize
+	}
+
+	ellipsis := policy.Ellipsis
+	if ellipsis == "" {
+		ellipsis = "…"
+	}
+
+	cp := msg.Clone()
+	var truncated []TruncatedField
+	for _, f := range cp.truncatableFields() {
+		excess := cp.PayloadSize() - maxSize
+		if excess <= 0 {
+			break
+		}
+
+		if *f.value == "" {
+			continue
+		}
+
+		originalSize := len(*f.value)
+		*f.value = truncateString(*f.value, excess, ellipsis)
+		truncated = append(truncated, TruncatedField{
+			Path:         f.path,
+			OriginalSize: originalSize,
+			Size:         len(*f.value),
+		})
+	}
+
+	if size := cp.PayloadSize(); size > maxSize {
+		return truncated, fmt.Errorf("%w: %d bytes after truncation, at most %d are allowed",
+			ErrPayloadTooLarge, size, maxSize)
+	}
+
+	// only texts are copied back, so nested structs of the message aren't replaced
+	fields := msg.truncatableFields()
+	for i, f := range cp.truncatableFields() {
+		*fields[i].value = *f.value
+	}
+
+	return truncated, nil
+}
+
+type truncatableField struct {
+	path  string
+	value *string
+}
+
+// truncatableFields returns texts which could be shortened in the order of truncation.
+func (msg *Message) truncatableFields() []truncatableField {
+	var bodies, titles []truncatableField
+	if n := msg.Notification; n != nil {
+		bodies = append(bodies, truncatableField{"message.notification.body", &n.Body})
+		titles = append(titles, truncatableField{"message.notification.title", &n.Title})
+	}
+
+	if msg.Android != nil && msg.Android.Notification != nil {
+		n := msg.Android.Notification
+		bodies = append(bodies, truncatableField{"message.android.notification.body", &n.Body})
+		titles = append(titles, truncatableField{"message.android.notification.title", &n.Title})
+	}
+
+	return append(bodies, titles...)
+}
+
+// truncateString cuts at least excess bytes from s including the appended ellipsis.
+// An empty string is returned if s is too short to keep anything.
+func truncateString(s string, excess int, ellipsis string) string {
+	n := len(s) - excess - len(ellipsis)
+	if n <= 0 {
+		return ""
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n] + ellipsis
+}
@@ -0,0 +1,70 @@
+package fcm
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Payload", func() {
+	It("should count data and notification fields", func() {
+		msg := &Message{
+			Token:        "token",
+			Data:         map[string]string{"k": "vv"},
+			Notification: &Notification{Title: "abc"},
+		}
+
+		Ω(msg.PayloadSize()).Should(Equal(len("k") + len("vv") + len("title") + len("abc")))
+	})
+
+	Context("Truncate func", func() {
+		var msg *Message
+
+		BeforeEach(func() {
+			msg = &Message{
+				Token: "token",
+				Notification: &Notification{
+					Title: "title",
+					Body:  strings.Repeat("ж", 100),
+				},
+			}
+		})
+
+		It("should keep message which fits", func() {
+			truncated, err := msg.Truncate(TruncatePolicy{})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(truncated).Should(BeEmpty())
+		})
+
+		It("should shorten body on rune boundary", func() {
+			truncated, err := msg.Truncate(TruncatePolicy{MaxSize: 100})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(msg.PayloadSize()).Should(BeNumerically("<=", 100))
+			Ω(utf8.ValidString(msg.Notification.Body)).Should(BeTrue())
+			Ω(msg.Notification.Body).Should(HaveSuffix("…"))
+			Ω(msg.Notification.Title).Should(Equal("title"))
+			Ω(truncated).Should(Equal([]TruncatedField{{
+				Path:         "message.notification.body",
+				OriginalSize: 200,
+				Size:         len(msg.Notification.Body),
+			}}))
+		})
+
+		It("should fail if data doesn't fit and keep the message intact", func() {
+			msg.Data = map[string]string{"key": strings.Repeat("x", 100)}
+			_, err := msg.Truncate(TruncatePolicy{MaxSize: 100})
+			Ω(errors.Is(err, ErrPayloadTooLarge)).Should(BeTrue())
+			Ω(msg.Notification.Body).Should(Equal(strings.Repeat("ж", 100)))
+			Ω(msg.Notification.Title).Should(Equal("title"))
+		})
+
+		It("should fail on nil message", func() {
+			var msg *Message
+			_, err := msg.Truncate(TruncatePolicy{})
+			Ω(err).Should(Equal(ErrInvalidMessage))
+		})
+	})
+})
@@ -0,0 +1,343 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrInvalidSchedule occurs if the send time can't be scheduled.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// ScheduledMessage is a message held by the Scheduler until its send time.
+type ScheduledMessage struct {
+	ID        string    `json:"id"`
+	Message   *Message  `json:"message"`
+	SendAt    time.Time `json:"send_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ScheduleStore persists scheduled messages. Implementations must be safe for concurrent use.
+type ScheduleStore interface {
+	// Put saves the scheduled message, replacing an existing one with the same ID.
+	Put(sm *ScheduledMessage) error
+	// Get returns the scheduled message by ID or ErrEntryNotFound.
+	Get(id string) (*ScheduledMessage, error)
+	// Delete removes the scheduled message by ID or returns ErrEntryNotFound.
+	Delete(id string) error
+	// Due returns at most limit messages to be sent at the time, ordered by send time.
+	Due(now time.Time, limit int) ([]*ScheduledMessage, error)
+	// Next returns the earliest send time, false if the store is empty.
+	Next() (time.Time, bool, error)
+}
+
+var _ ScheduleStore = (*MemoryScheduleStore)(nil)
+
+// MemoryScheduleStore keeps scheduled messages in memory, they are lost on restart.
+type MemoryScheduleStore struct {
+	mu       sync.RWMutex
+	messages map[string]ScheduledMessage
+}
+
+// NewMemoryScheduleStore returns an empty in-memory store.
+func NewMemoryScheduleStore() *MemoryScheduleStore {
+	return &MemoryScheduleStore{messages: make(map[string]ScheduledMessage)}
+}
+
+func (s *MemoryScheduleStore) Put(sm *ScheduledMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages[sm.ID] = *sm
+	return nil
+}
+
+func (s *MemoryScheduleStore) Get(id string) (*ScheduledMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sm, ok := s.messages[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrEntryNotFound, id)
+	}
+
+	return &sm, nil
+}
+
+func (s *MemoryScheduleStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.messages[id]; !ok {
+		return fmt.Errorf("%w: %q", ErrEntryNotFound, id)
+	}
+
+	delete(s.messages, id)
+	return nil
+}
+
+func (s *MemoryScheduleStore) Due(now time.Time, limit int) ([]*ScheduledMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var due []*ScheduledMessage
+	for _, sm := range s.messages {
+		if !sm.SendAt.After(now) {
+			sm := sm
+			due = append(due, &sm)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].SendAt.Equal(due[j].SendAt) {
+			return due[i].SendAt.Before(due[j].SendAt)
+		}
+
+		return due[i].ID < due[j].ID
+	})
+
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	return due, nil
+}
+
+func (s *MemoryScheduleStore) Next() (time.Time, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var next time.Time
+	found := false
+	for _, sm := range s.messages {
+		if !found || sm.SendAt.Before(next) {
+			next, found = sm.SendAt, true
+		}
+	}
+
+	return next, found, nil
+}
+
+// Scheduler holds messages in the ScheduleStore and sends them using the client
+// when their send time comes. A message is removed from the store right before
+// it is sent, so it is sent at most once; failed sends are reported to the error handler.
+type Scheduler struct {
+	client Client
+	store  ScheduleStore
+	clock  Clock
+
+	batchSize int
+	onError   func(sm *ScheduledMessage, err error)
+
+	wake chan struct{}
+}
+
+// SchedulerOption configures Scheduler with defined option.
+type SchedulerOption func(*Scheduler)
+
+// WithSchedulerClock returns SchedulerOption to configure the clock, e.g. ManualClock in tests.
+func WithSchedulerClock(clock Clock) SchedulerOption {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// WithSchedulerErrorHandler returns SchedulerOption to configure the handler
+// called when the scheduled message fails to be sent.
+func WithSchedulerErrorHandler(fn func(sm *ScheduledMessage, err error)) SchedulerOption {
+	return func(s *Scheduler) {
+		s.onError = fn
+	}
+}
+
+// NewScheduler creates Scheduler which sends messages held in the store using the client.
+func NewScheduler(client Client, store ScheduleStore, opts ...SchedulerOption) *Scheduler {
+	s := Scheduler{
+		client:    client,
+		store:     store,
+		clock:     RealClock,
+		batchSize: 100,
+		onError:   func(*ScheduledMessage, error) {},
+		wake:      make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	return &s
+}
+
+// Schedule validates and stores the message to be sent at the time.
+// It returns ID of the scheduled message which can be used to cancel it.
+func (s *Scheduler) Schedule(msg *Message, at time.Time) (string, error) {
+	if at.IsZero() {
+		return "", fmt.Errorf("%w: send time is not set", ErrInvalidSchedule)
+	}
+
+	if err := msg.Validate(); err != nil {
+		return "", fmt.Errorf("invalid message: %w", err)
+	}
+
+	id, err := newEntryID()
+	if err != nil {
+		return "", err
+	}
+
+	sm := ScheduledMessage{
+		ID:        id,
+		Message:   msg.Clone(),
+		SendAt:    at,
+		CreatedAt: s.clock.Now(),
+	}
+
+	if err := s.store.Put(&sm); err != nil {
+		return "", fmt.Errorf("failed to schedule message: %w", err)
+	}
+
+	s.notify()
+	return id, nil
+}
+
+// ScheduleAfter stores the message to be sent after the delay.
+func (s *Scheduler) ScheduleAfter(msg *Message, delay time.Duration) (string, error) {
+	return s.Schedule(msg, s.clock.Now().Add(delay))
+}
+
+// ScheduleLocal stores the message to be sent at the next occurrence of the wall
+// clock time in the recipient's location, e.g. at 9:00 in "Europe/Berlin".
+func (s *Scheduler) ScheduleLocal(msg *Message, hour, minute int, loc *time.Location) (string, error) {
+	at, err := nextLocalTime(s.clock.Now(), hour, minute, loc)
+	if err != nil {
+		return "", err
+	}
+
+	return s.Schedule(msg, at)
+}
+
+// Cancel removes the scheduled message, ErrEntryNotFound is returned
+// if it is already sent or doesn't exist.
+func (s *Scheduler) Cancel(id string) error {
+	if err := s.store.Delete(id); err != nil {
+		return err
+	}
+
+	s.notify()
+	return nil
+}
+
+// Run sends scheduled messages when they are due until the context is done.
+// It returns the first store error or the context error.
+func (s *Scheduler) Run(ctx context.Context) error {
+	// the timer is reused, so wake ups don't leave pending timers behind
+	var (
+		timer Timer
+		armed bool
+	)
+
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		if err := s.dispatch(ctx); err != nil {
+			return err
+		}
+
+		next, ok, err := s.store.Next()
+		if err != nil {
+			return fmt.Errorf("failed to get next scheduled message: %w", err)
+		}
+
+		var timerC <-chan time.Time
+		if ok {
+			d := next.Sub(s.clock.Now())
+			if timer == nil {
+				timer = s.clock.NewTimer(d)
+			} else {
+				timer.Reset(d)
+			}
+
+			armed, timerC = true, timer.C()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timerC:
+			armed = false
+		case <-s.wake:
+		}
+
+		if armed && !timer.Stop() {
+			<-timer.C()
+		}
+
+		armed = false
+	}
+}
+
+// dispatch sends all due messages.
+func (s *Scheduler) dispatch(ctx context.Context) error {
+	for {
+		due, err := s.store.Due(s.clock.Now(), s.batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get due messages: %w", err)
+		}
+
+		if len(due) == 0 {
+			return nil
+		}
+
+		for _, sm := range due {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if err := s.store.Delete(sm.ID); err != nil {
+				if errors.Is(err, ErrEntryNotFound) {
+					// canceled concurrently
+					continue
+				}
+
+				return fmt.Errorf("failed to delete scheduled message: %w", err)
+			}
+
+			if err := s.client.Send(ctx, sm.Message); err != nil {
+				s.onError(sm, err)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// nextLocalTime returns the first moment after now when the wall clock
+// in the location shows hour:minute.
+func nextLocalTime(now time.Time, hour, minute int, loc *time.Location) (time.Time, error) {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return time.Time{}, fmt.Errorf("%w: local time %02d:%02d", ErrInvalidSchedule, hour, minute)
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	local := now.In(loc)
+	at := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !at.After(now) {
+		at = time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, loc)
+	}
+
+	return at, nil
+}
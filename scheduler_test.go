@@ -0,0 +1,112 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduler", func() {
+	var (
+		client    *ClientMock
+		clock     *ManualClock
+		scheduler *Scheduler
+		sent      chan *Message
+		cancel    context.CancelFunc
+		done      chan error
+	)
+
+	start := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		sent = make(chan *Message, 10)
+		client = NewClientMock(GinkgoT())
+		client.SendMock.Set(func(_ context.Context, msg *Message, _ ...SendOption) error {
+			sent <- msg
+			return nil
+		})
+
+		clock = NewManualClock(start)
+		scheduler = NewScheduler(client, NewMemoryScheduleStore(), WithSchedulerClock(clock))
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		done = make(chan error, 1)
+		go func() {
+			done <- scheduler.Run(ctx)
+		}()
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(done).Should(Receive(Equal(context.Canceled)))
+	})
+
+	It("should send message on time", func() {
+		_, err := scheduler.ScheduleAfter(&Message{Token: "later"}, time.Hour)
+		Ω(err).ShouldNot(HaveOccurred())
+		_, err = scheduler.ScheduleAfter(&Message{Token: "sooner"}, time.Minute)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Consistently(sent, 50*time.Millisecond).ShouldNot(Receive())
+
+		clock.Advance(time.Minute)
+		Eventually(sent).Should(Receive(Equal(&Message{Token: "sooner"})))
+		Consistently(sent, 50*time.Millisecond).ShouldNot(Receive())
+
+		clock.Advance(time.Hour)
+		Eventually(sent).Should(Receive(Equal(&Message{Token: "later"})))
+	})
+
+	It("should not send canceled message", func() {
+		id, err := scheduler.ScheduleAfter(&Message{Token: "token"}, time.Minute)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(scheduler.Cancel(id)).Should(Succeed())
+
+		clock.Advance(time.Hour)
+		Consistently(sent, 50*time.Millisecond).ShouldNot(Receive())
+		Ω(errors.Is(scheduler.Cancel(id), ErrEntryNotFound)).Should(BeTrue())
+	})
+
+	It("should reuse the timer on wake ups", func() {
+		for i := 0; i < 10; i++ {
+			id, err := scheduler.ScheduleAfter(&Message{Token: "token"}, time.Hour)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(scheduler.Cancel(id)).Should(Succeed())
+		}
+
+		_, err := scheduler.ScheduleAfter(&Message{Token: "token"}, time.Hour)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		pendingTimers := func() int {
+			clock.mu.Lock()
+			defer clock.mu.Unlock()
+			return len(clock.timers)
+		}
+
+		Eventually(pendingTimers).Should(Equal(1))
+		Consistently(pendingTimers, 50*time.Millisecond).Should(Equal(1))
+
+		clock.Advance(time.Hour)
+		Eventually(sent).Should(Receive())
+		Eventually(pendingTimers).Should(Equal(0))
+	})
+
+	It("should schedule at recipient local time", func() {
+		newYork := time.FixedZone("EST", -5*60*60)
+		at, err := nextLocalTime(start, 9, 0, newYork)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(at).Should(BeTemporally("==", time.Date(2020, 3, 1, 14, 0, 0, 0, time.UTC)))
+
+		tokyo := time.FixedZone("JST", 9*60*60)
+		at, err = nextLocalTime(start, 9, 0, tokyo)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(at).Should(BeTemporally("==", time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC)))
+
+		_, err = scheduler.ScheduleLocal(&Message{Token: "token"}, 24, 0, tokyo)
+		Ω(errors.Is(err, ErrInvalidSchedule)).Should(BeTrue())
+	})
+})
@@ -0,0 +1,86 @@
+package fcm
+
+import (
+	"time"
+)
+
+// RetryPolicy configures retries of sends failed with temporary errors:
+// transport errors and responses with 429 or 5xx status codes.
+type RetryPolicy struct {
+	// MaxAttempts is a total number of attempts, values less than 2 disable retries.
+	MaxAttempts int
+	// Backoff returns delay before the next attempt, e.g. ExponentialBackoff.
+	Backoff func(attempt int) time.Duration
+}
+
+// SendOptions are per-call options of the send. They are exported for
+// Client implementations and mocks, use SendOption funcs to set them.
+type SendOptions struct {
+	// Timeout limits duration of the send including retries.
+	Timeout time.Duration
+	// Headers are added to the HTTP request.
+	Headers map[string]string
+	// ValidateOnly validates the message by FCM server without delivering it.
+	ValidateOnly bool
+	// Retry overrides the client retry policy.
+	Retry *RetryPolicy
+	// IdempotencyKey is a deduplication key used by IdempotentClient.
+	IdempotencyKey string
+}
+
+// SendOption configures a single send.
+type SendOption func(*SendOptions)
+
+// ApplySendOptions returns options set by opts.
+func ApplySendOptions(opts ...SendOption) SendOptions {
+	var o SendOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithSendTimeout returns SendOption to limit duration of the send.
+func WithSendTimeout(d time.Duration) SendOption {
+	return func(o *SendOptions) {
+		o.Timeout = d
+	}
+}
+
+// WithSendHeader returns SendOption to add the header to the HTTP request.
+// Authorization and Content-Type headers can't be overridden.
+func WithSendHeader(key, value string) SendOption {
+	return func(o *SendOptions) {
+		headers := make(map[string]string, len(o.Headers)+1)
+		for k, v := range o.Headers {
+			headers[k] = v
+		}
+
+		headers[key] = value
+		o.Headers = headers
+	}
+}
+
+// WithSendValidateOnly returns SendOption to validate the message by FCM server
+// without delivering it to the device.
+func WithSendValidateOnly() SendOption {
+	return func(o *SendOptions) {
+		o.ValidateOnly = true
+	}
+}
+
+// WithSendRetry returns SendOption to override the client retry policy.
+func WithSendRetry(policy RetryPolicy) SendOption {
+	return func(o *SendOptions) {
+		o.Retry = &policy
+	}
+}
+
+// WithSendIdempotencyKey returns SendOption to set deduplication key of the send,
+// see IdempotentClient.
+func WithSendIdempotencyKey(key string) SendOption {
+	return func(o *SendOptions) {
+		o.IdempotencyKey = key
+	}
+}
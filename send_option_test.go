@@ -0,0 +1,141 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/oauth2"
+)
+
+var _ = Describe("SendOption", func() {
+	var (
+		requests []*fasthttp.Request
+		statuses []int
+		client   *SimpleClient
+	)
+
+	BeforeEach(func() {
+		requests, statuses = nil, nil
+		client = newClient(
+			WithEndpoint(DefaultEndpoint),
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: func(int) time.Duration { return 0 }}),
+			WithHTTPClient(doerFunc(func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+				r := fasthttp.AcquireRequest()
+				req.CopyTo(r)
+				requests = append(requests, r)
+
+				status := fasthttp.StatusOK
+				if len(statuses) > 0 {
+					status, statuses = statuses[0], statuses[1:]
+				}
+
+				resp.SetStatusCode(status)
+				resp.SetBodyString(`{"name": "projects/p/messages/1"}`)
+				return ctx.Err()
+			})))
+		client.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"})
+	})
+
+	It("should add headers without overriding authorization", func() {
+		Ω(client.Send(context.Background(), &Message{Token: "token"},
+			WithSendHeader("X-Request-Id", "1"),
+			WithSendHeader("Authorization", "Bearer other"))).Should(Succeed())
+
+		Ω(requests).Should(HaveLen(1))
+		Ω(string(requests[0].Header.Peek("X-Request-Id"))).Should(Equal("1"))
+		Ω(string(requests[0].Header.Peek("Authorization"))).Should(Equal("Bearer access"))
+	})
+
+	It("should validate only", func() {
+		Ω(client.SendDryRun(context.Background(), &Message{Token: "token"})).Should(Succeed())
+		Ω(client.Send(context.Background(), &Message{Token: "token"}, WithSendValidateOnly())).Should(Succeed())
+
+		Ω(requests).Should(HaveLen(2))
+		for _, req := range requests {
+			Ω(string(req.Body())).Should(ContainSubstring(`"validate_only":true`))
+		}
+	})
+
+	It("should apply the timeout", func() {
+		client.client = doerFunc(func(ctx context.Context, _ *fasthttp.Request, _ *fasthttp.Response) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		err := client.Send(context.Background(), &Message{Token: "token"}, WithSendTimeout(time.Millisecond))
+		Ω(errors.Is(err, context.DeadlineExceeded)).Should(BeTrue())
+	})
+
+	It("should retry temporary errors with the client policy", func() {
+		statuses = []int{fasthttp.StatusServiceUnavailable}
+		Ω(client.Send(context.Background(), &Message{Token: "token"})).Should(Succeed())
+		Ω(requests).Should(HaveLen(2))
+	})
+
+	It("should override the retry policy", func() {
+		statuses = []int{fasthttp.StatusServiceUnavailable}
+		Ω(client.Send(context.Background(), &Message{Token: "token"},
+			WithSendRetry(RetryPolicy{MaxAttempts: 1}))).ShouldNot(Succeed())
+		Ω(requests).Should(HaveLen(1))
+	})
+
+	It("should not retry permanent errors", func() {
+		statuses = []int{fasthttp.StatusBadRequest}
+		Ω(client.Send(context.Background(), &Message{Token: "token"})).ShouldNot(Succeed())
+		Ω(requests).Should(HaveLen(1))
+	})
+
+	It("should not mutate the options", func() {
+		opt := WithSendHeader("A", "1")
+		o := ApplySendOptions(opt, WithSendHeader("B", "2"))
+		Ω(o.Headers).Should(Equal(map[string]string{"A": "1", "B": "2"}))
+		Ω(ApplySendOptions(opt).Headers).Should(Equal(map[string]string{"A": "1"}))
+	})
+
+	It("should be recorded by the fake client", func() {
+		fake := NewFakeClient()
+		Ω(fake.Send(context.Background(), &Message{Token: "token"}, WithSendValidateOnly())).Should(Succeed())
+		Ω(fake.Sent()).Should(BeEmpty())
+		Ω(fake.Calls()).Should(HaveLen(1))
+		Ω(fake.Calls()[0].Options.ValidateOnly).Should(BeTrue())
+	})
+
+	It("should be matched by the mock", func() {
+		ctx, msg := context.Background(), &Message{Token: "token"}
+		mock := NewClientMock(GinkgoT())
+		expectSendOptions(mock, ErrUnregistered, WithSendTimeout(time.Second), WithSendHeader("A", "1"))
+
+		Ω(mock.Send(ctx, msg, WithSendTimeout(time.Second), WithSendHeader("A", "1"))).Should(Equal(ErrUnregistered))
+		Ω(mock.Send(ctx, msg, WithSendTimeout(time.Second))).Should(MatchError(ContainSubstring("unexpected send options")))
+		Ω(mock.SendAfterCounter()).Should(BeEquivalentTo(2))
+	})
+
+	It("should deduplicate sends by the idempotency key", func() {
+		fake := NewFakeClient()
+		client := NewIdempotentClient(fake)
+		for i := 0; i < 2; i++ {
+			Ω(client.Send(context.Background(), &Message{Token: "token"}, WithSendIdempotencyKey("key"))).Should(Succeed())
+		}
+
+		Ω(fake.Sent()).Should(HaveLen(1))
+	})
+})
+
+// expectSendOptions sets the mock to return err for sends with the options and to fail
+// sends with other ones. SendOption funcs are not comparable, so Expect can't match them.
+func expectSendOptions(mock *ClientMock, err error, opts ...SendOption) {
+	expected := ApplySendOptions(opts...)
+	mock.SendMock.Set(func(_ context.Context, _ *Message, got ...SendOption) error {
+		if actual := ApplySendOptions(got...); !reflect.DeepEqual(actual, expected) {
+			return fmt.Errorf("unexpected send options: %+v, expected %+v", actual, expected)
+		}
+
+		return err
+	})
+}
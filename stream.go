@@ -0,0 +1,154 @@
+package fcm
+
+import (
+	"context"
+	"sync"
+)
+
+// Result is a result of the message sent by SendStream.
+type Result struct {
+	// Index is a sequence number of the message read from the input channel.
+	Index   int
+	Message *Message
+	Err     error
+}
+
+type streamConfig struct {
+	concurrency int
+	ordered     bool
+}
+
+// StreamOption configures SendStream with defined option.
+type StreamOption func(*streamConfig)
+
+// WithStreamConcurrency returns StreamOption to configure number of concurrent sends.
+func WithStreamConcurrency(n int) StreamOption {
+	return func(cfg *streamConfig) {
+		if n > 0 {
+			cfg.concurrency = n
+		}
+	}
+}
+
+// WithStreamOrdered returns StreamOption to configure whether results are emitted
+// in the order of the input messages, otherwise they are emitted as soon as sent.
+func WithStreamOrdered(ordered bool) StreamOption {
+	return func(cfg *streamConfig) {
+		cfg.ordered = ordered
+	}
+}
+
+type streamJob struct {
+	index int
+	msg   *Message
+}
+
+// send sends the message unless the context is done.
+func (job streamJob) send(ctx context.Context, c Client) Result {
+	err := ctx.Err()
+	if err == nil {
+		err = c.Send(ctx, job.msg)
+	}
+
+	return Result{Index: job.index, Message: job.msg, Err: err}
+}
+
+// SendStream sends messages read from the input channel using the client and
+// emits their results. At most concurrency messages are read ahead of results
+// being received, so a slow consumer slows down reading of the input.
+//
+// The results channel is closed when the input channel is closed or the context
+// is done and results of all messages read by then are emitted, so it must be drained
+// until it is closed. Messages read but not sent yet when the context is done
+// are emitted with the context error without sending, messages left in the input
+// channel are not read.
+func SendStream(ctx context.Context, c Client, in <-chan *Message, opts ...StreamOption) <-chan Result {
+	cfg := streamConfig{concurrency: 4}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	jobs := make(chan streamJob)
+	results := make(chan Result)
+	out := make(chan Result)
+
+	// limits messages read but not emitted yet
+	window := make(chan struct{}, cfg.concurrency)
+
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			// select picks ready cases randomly, so don't read after the context is done
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case msg, ok := <-in:
+				if !ok {
+					return
+				}
+
+				jobs <- streamJob{index: index, msg: msg}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				results <- job.send(ctx, c)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	go func() {
+		defer close(out)
+
+		pending := make(map[int]Result)
+		next := 0
+		for res := range results {
+			if !cfg.ordered {
+				out <- res
+				<-window
+				continue
+			}
+
+			pending[res.Index] = res
+			for {
+				res, ok := pending[next]
+				if !ok {
+					break
+				}
+
+				delete(pending, next)
+				out <- res
+				<-window
+				next++
+			}
+		}
+	}()
+
+	return out
+}
+
+// SendStream sends messages read from the input channel, see SendStream func for details.
+func (c *SimpleClient) SendStream(ctx context.Context, in <-chan *Message, opts ...StreamOption) <-chan Result {
+	return SendStream(ctx, c, in, opts...)
+}
@@ -0,0 +1,132 @@
+package fcm
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SendStream", func() {
+	var client *ClientMock
+
+	BeforeEach(func() {
+		client = NewClientMock(GinkgoT())
+	})
+
+	produce := func(n int) <-chan *Message {
+		in := make(chan *Message)
+		go func() {
+			defer close(in)
+			for i := 0; i < n; i++ {
+				in <- &Message{Token: fmt.Sprint(i)}
+			}
+		}()
+
+		return in
+	}
+
+	It("should emit results in input order", func() {
+		client.SendMock.Set(func(_ context.Context, msg *Message, _ ...SendOption) error {
+			// earlier messages are sent slower
+			if msg.Token < "5" {
+				time.Sleep(5 * time.Millisecond)
+			}
+
+			if msg.Token == "3" {
+				return ErrUnregistered
+			}
+
+			return nil
+		})
+
+		var indexes []int
+		for res := range SendStream(context.Background(), client, produce(10),
+			WithStreamConcurrency(3), WithStreamOrdered(true)) {
+			Ω(res.Message.Token).Should(Equal(fmt.Sprint(res.Index)))
+			if res.Index == 3 {
+				Ω(res.Err).Should(Equal(ErrUnregistered))
+			} else {
+				Ω(res.Err).ShouldNot(HaveOccurred())
+			}
+
+			indexes = append(indexes, res.Index)
+		}
+
+		Ω(indexes).Should(Equal([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}))
+	})
+
+	It("should limit concurrency and read ahead", func() {
+		var active, maxActive int32
+		client.SendMock.Set(func(context.Context, *Message, ...SendOption) error {
+			n := atomic.AddInt32(&active, 1)
+			for {
+				max := atomic.LoadInt32(&maxActive)
+				if n <= max || atomic.CompareAndSwapInt32(&maxActive, max, n) {
+					break
+				}
+			}
+
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&active, -1)
+			return nil
+		})
+
+		out := SendStream(context.Background(), client, produce(20), WithStreamConcurrency(2))
+
+		// nobody reads results, so only the window of messages is sent
+		Eventually(client.SendAfterCounter).Should(BeEquivalentTo(2))
+		Consistently(client.SendAfterCounter, 20*time.Millisecond).Should(BeEquivalentTo(2))
+
+		count := 0
+		for range out {
+			count++
+		}
+
+		Ω(count).Should(Equal(20))
+		Ω(atomic.LoadInt32(&maxActive)).Should(BeNumerically("<=", 2))
+	})
+
+	It("should drain in-flight sends on cancel", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		client.SendMock.Set(func(context.Context, *Message, ...SendOption) error {
+			return nil
+		})
+
+		in := make(chan *Message, 10)
+		for i := 0; i < 10; i++ {
+			in <- &Message{Token: fmt.Sprint(i)}
+		}
+
+		out := SendStream(ctx, client, in, WithStreamConcurrency(2))
+		Eventually(out).Should(Receive())
+		cancel()
+
+		count, sent := 1, 1
+		for res := range out {
+			count++
+			if res.Err == nil {
+				sent++
+			} else {
+				Ω(res.Err).Should(Equal(context.Canceled))
+			}
+		}
+
+		Ω(count).Should(BeNumerically("<=", 3))
+		Ω(client.SendAfterCounter()).Should(BeEquivalentTo(sent))
+		Ω(len(in)).Should(Equal(10 - count))
+	})
+
+	It("should emit canceled result for messages read after cancel without sending", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		msg := &Message{Token: "token"}
+		res := streamJob{index: 3, msg: msg}.send(ctx, client)
+		Ω(res).Should(Equal(Result{Index: 3, Message: msg, Err: context.Canceled}))
+		Ω(client.SendBeforeCounter()).Should(BeEquivalentTo(0))
+	})
+})
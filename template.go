@@ -0,0 +1,159 @@
+package fcm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Template is a Message whose text fields contain text/template expressions,
+// e.g. Notification.Title = "Hi, {{.name}}". Templates are compiled once and rendered
+// per recipient with a variables map. Rendering fails if a variable is missing.
+// Output is not HTML escaped.
+type Template struct {
+	msg       *Message
+	templates map[string]*template.Template
+}
+
+// Recipient is a device token with variables to render the template for it.
+type Recipient struct {
+	Token string
+	Vars  map[string]interface{}
+}
+
+// NewTemplate compiles templates of the message text fields: notification title,
+// body and image, data values and Android notification texts. The message is copied,
+// so it could be reused after the call.
+func NewTemplate(msg *Message) (*Template, error) {
+	if msg == nil {
+		return nil, ErrInvalidMessage
+	}
+
+	t := Template{
+		msg:       msg.Clone(),
+		templates: make(map[string]*template.Template),
+	}
+
+	var err error
+	t.msg.walkTextFields(func(path string, value string, _ func(string)) {
+		if err != nil || !strings.Contains(value, "{{") {
+			return
+		}
+
+		tmpl, parseErr := template.New(path).Option("missingkey=error").Parse(value)
+		if parseErr != nil {
+			err = fmt.Errorf("failed to parse template of %s: %w", path, parseErr)
+			return
+		}
+
+		t.templates[path] = tmpl
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// Render returns a new message with the templates executed using vars.
+func (t *Template) Render(vars map[string]interface{}) (*Message, error) {
+	msg := t.msg.Clone()
+
+	var err error
+	var sb strings.Builder
+	msg.walkTextFields(func(path string, _ string, set func(string)) {
+		tmpl, ok := t.templates[path]
+		if err != nil || !ok {
+			return
+		}
+
+		sb.Reset()
+		if execErr := tmpl.Execute(&sb, vars); execErr != nil {
+			err = fmt.Errorf("failed to render template of %s: %w", path, execErr)
+			return
+		}
+
+		set(sb.String())
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// SendEach renders the template for every recipient and sends it to the recipient token.
+// It returns errors in the same order as recipients, nil for successfully sent messages.
+func (t *Template) SendEach(ctx context.Context, c Client, recipients []Recipient) []error {
+	errs := make([]error, len(recipients))
+	for i, r := range recipients {
+		msg, err := t.Render(r.Vars)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		msg.Token, msg.Topic, msg.Condition = r.Token, "", ""
+		errs[i] = c.Send(ctx, msg)
+	}
+
+	return errs
+}
+
+// walkTextFields calls fn for every non-empty templatable text field of the message,
+// set replaces the field value. Map values are visited in order of keys.
+func (msg *Message) walkTextFields(fn func(path, value string, set func(string))) {
+	walkString := func(path string, s *string) {
+		if *s != "" {
+			fn(path, *s, func(v string) { *s = v })
+		}
+	}
+
+	walkMap := func(path string, m map[string]string) {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+			k := k
+			fn(path+"."+k, m[k], func(v string) { m[k] = v })
+		}
+	}
+
+	walkSlice := func(path string, ss []string) {
+		for i := range ss {
+			walkString(fmt.Sprintf("%s[%d]", path, i), &ss[i])
+		}
+	}
+
+	walkMap("message.data", msg.Data)
+
+	if n := msg.Notification; n != nil {
+		walkString("message.notification.title", &n.Title)
+		walkString("message.notification.body", &n.Body)
+		walkString("message.notification.image", &n.Image)
+	}
+
+	if msg.Android == nil {
+		return
+	}
+
+	walkMap("message.android.data", msg.Android.Data)
+
+	if n := msg.Android.Notification; n != nil {
+		walkString("message.android.notification.title", &n.Title)
+		walkString("message.android.notification.body", &n.Body)
+		walkString("message.android.notification.image", &n.Image)
+		walkString("message.android.notification.click_action", &n.ClickAction)
+		walkString("message.android.notification.tag", &n.Tag)
+		walkString("message.android.notification.ticker", &n.Ticker)
+		walkSlice("message.android.notification.title_loc_args", n.TitleLocArgs)
+		walkSlice("message.android.notification.body_loc_args", n.BodyLocArgs)
+	}
+}
@@ -0,0 +1,75 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template", func() {
+	var tmpl *Template
+
+	BeforeEach(func() {
+		var err error
+		tmpl, err = NewTemplate(&Message{
+			Data:         map[string]string{"link": "app://orders/{{.order}}", "static": "{x}"},
+			Notification: &Notification{Title: "Hi, {{.name}}", Body: "Your order is ready"},
+			Android: &AndroidConfig{Notification: &AndroidNotification{
+				BodyLocArgs: []string{"{{.name}}"},
+			}},
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("should render message for each recipient", func() {
+		msg, err := tmpl.Render(map[string]interface{}{"name": "<Tom & Jerry>", "order": 42})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(msg.Notification.Title).Should(Equal("Hi, <Tom & Jerry>"))
+		Ω(msg.Notification.Body).Should(Equal("Your order is ready"))
+		Ω(msg.Data).Should(Equal(map[string]string{"link": "app://orders/42", "static": "{x}"}))
+		Ω(msg.Android.Notification.BodyLocArgs).Should(Equal([]string{"<Tom & Jerry>"}))
+
+		other, err := tmpl.Render(map[string]interface{}{"name": "Alice", "order": 1})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(other.Notification.Title).Should(Equal("Hi, Alice"))
+		Ω(msg.Notification.Title).Should(Equal("Hi, <Tom & Jerry>"))
+	})
+
+	It("should fail on missing variable", func() {
+		_, err := tmpl.Render(map[string]interface{}{"name": "Alice"})
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring("message.data.link"))
+	})
+
+	It("should fail on malformed template", func() {
+		_, err := NewTemplate(&Message{Notification: &Notification{Title: "{{.name"}})
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("should send rendered message to each recipient", func() {
+		sendErr := errors.New("send failed")
+		client := NewClientMock(GinkgoT())
+		client.SendMock.Set(func(_ context.Context, msg *Message, _ ...SendOption) error {
+			if msg.Token == "bad" {
+				return sendErr
+			}
+
+			Ω(msg.Notification.Title).Should(Equal("Hi, " + msg.Token))
+			return nil
+		})
+
+		errs := tmpl.SendEach(context.Background(), client, []Recipient{
+			{Token: "Alice", Vars: map[string]interface{}{"name": "Alice", "order": 1}},
+			{Token: "Bob", Vars: map[string]interface{}{"name": "Bob"}},
+			{Token: "bad", Vars: map[string]interface{}{"name": "bad", "order": 2}},
+		})
+
+		Ω(errs).Should(HaveLen(3))
+		Ω(errs[0]).ShouldNot(HaveOccurred())
+		Ω(errs[1]).Should(HaveOccurred())
+		Ω(errs[2]).Should(Equal(sendErr))
+		Ω(client.SendAfterCounter()).Should(BeEquivalentTo(2))
+	})
+})
@@ -0,0 +1,240 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TokenRegistry tracks health of registration tokens based on send results.
+// Implementations must be safe for concurrent use.
+type TokenRegistry interface {
+	// MarkSuccess records the successful send to the token.
+	MarkSuccess(token string, at time.Time)
+	// MarkInvalid records the token is stale with the reason:
+	// ErrUnregistered, ErrInvalidToken or ErrSenderIDMismatch.
+	MarkInvalid(token string, reason error, at time.Time)
+}
+
+// IsInvalidTokenError reports whether the send failed because the registration token is stale.
+func IsInvalidTokenError(err error) bool {
+	return errors.Is(err, ErrUnregistered) || errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrSenderIDMismatch)
+}
+
+// trackToken reports the send result to the invalid token handler and the token registry.
+func (c *SimpleClient) trackToken(token string, err error) {
+	if token == "" {
+		return
+	}
+
+	switch {
+	case err == nil:
+		if c.tokenRegistry != nil {
+			c.tokenRegistry.MarkSuccess(token, c.clock.Now())
+		}
+	case IsInvalidTokenError(err):
+		if c.invalidTokenHandler != nil {
+			c.invalidTokenHandler(token, err)
+		}
+
+		if c.tokenRegistry != nil {
+			c.tokenRegistry.MarkInvalid(token, err, c.clock.Now())
+		}
+	}
+}
+
+// TokenState is a health state of the registration token.
+type TokenState string
+
+const (
+	TokenActive  TokenState = "ACTIVE"
+	TokenInvalid TokenState = "INVALID"
+)
+
+// TokenHealth describes the registration token known to the registry.
+type TokenHealth struct {
+	Token         string
+	State         TokenState
+	Reason        error
+	RegisteredAt  time.Time
+	LastSuccessAt time.Time
+	InvalidAt     time.Time
+}
+
+var _ TokenRegistry = (*MemoryTokenRegistry)(nil)
+
+// MemoryTokenRegistry is in-memory TokenRegistry. Tokens are pruned when they
+// are invalid or have no successful sends for the stale period.
+type MemoryTokenRegistry struct {
+	staleAfter time.Duration
+	clock      Clock
+	onPrune    func(health TokenHealth)
+
+	mu     sync.RWMutex
+	tokens map[string]*TokenHealth
+}
+
+// TokenRegistryOption configures MemoryTokenRegistry with defined option.
+type TokenRegistryOption func(*MemoryTokenRegistry)
+
+// WithTokenStaleAfter returns TokenRegistryOption to configure the period without
+// successful sends after which the token is pruned, zero disables it.
+func WithTokenStaleAfter(d time.Duration) TokenRegistryOption {
+	return func(r *MemoryTokenRegistry) {
+		r.staleAfter = d
+	}
+}
+
+// WithTokenPruneHandler returns TokenRegistryOption to configure the handler
+// called for every pruned token, e.g. to delete it from the application database.
+func WithTokenPruneHandler(fn func(health TokenHealth)) TokenRegistryOption {
+	return func(r *MemoryTokenRegistry) {
+		r.onPrune = fn
+	}
+}
+
+// WithTokenRegistryClock returns TokenRegistryOption to configure the clock.
+func WithTokenRegistryClock(clock Clock) TokenRegistryOption {
+	return func(r *MemoryTokenRegistry) {
+		r.clock = clock
+	}
+}
+
+// NewMemoryTokenRegistry returns an empty registry.
+// By default tokens without successful sends for 270 days are pruned
+// as FCM considers them expired.
+func NewMemoryTokenRegistry(opts ...TokenRegistryOption) *MemoryTokenRegistry {
+	r := MemoryTokenRegistry{
+		staleAfter: 270 * 24 * time.Hour,
+		clock:      RealClock,
+		onPrune:    func(TokenHealth) {},
+		tokens:     make(map[string]*TokenHealth),
+	}
+
+	for _, opt := range opts {
+		opt(&r)
+	}
+
+	return &r
+}
+
+// Register adds the token reported by the app, re-registering an invalid token makes it active.
+func (r *MemoryTokenRegistry) Register(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[token] = &TokenHealth{
+		Token:        token,
+		State:        TokenActive,
+		RegisteredAt: r.clock.Now(),
+	}
+}
+
+func (r *MemoryTokenRegistry) MarkSuccess(token string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := r.get(token, at)
+	h.State, h.Reason, h.InvalidAt = TokenActive, nil, time.Time{}
+	h.LastSuccessAt = at
+}
+
+func (r *MemoryTokenRegistry) MarkInvalid(token string, reason error, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := r.get(token, at)
+	h.State, h.Reason, h.InvalidAt = TokenInvalid, reason, at
+}
+
+// Health returns the token health, false if the token is unknown.
+func (r *MemoryTokenRegistry) Health(token string) (TokenHealth, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, ok := r.tokens[token]
+	if !ok {
+		return TokenHealth{}, false
+	}
+
+	return *h, true
+}
+
+// Active returns active tokens in lexical order.
+func (r *MemoryTokenRegistry) Active() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tokens []string
+	for token, h := range r.tokens {
+		if h.State == TokenActive {
+			tokens = append(tokens, token)
+		}
+	}
+
+	sort.Strings(tokens)
+	return tokens
+}
+
+// Prune removes invalid and stale tokens and returns them.
+func (r *MemoryTokenRegistry) Prune() []TokenHealth {
+	now := r.clock.Now()
+
+	r.mu.Lock()
+	var pruned []TokenHealth
+	for token, h := range r.tokens {
+		if h.State == TokenInvalid || r.isStale(h, now) {
+			pruned = append(pruned, *h)
+			delete(r.tokens, token)
+		}
+	}
+	r.mu.Unlock()
+
+	sort.Slice(pruned, func(i, j int) bool {
+		return pruned[i].Token < pruned[j].Token
+	})
+
+	for _, h := range pruned {
+		r.onPrune(h)
+	}
+
+	return pruned
+}
+
+// Run prunes tokens with the interval until the context is done.
+func (r *MemoryTokenRegistry) Run(ctx context.Context, interval time.Duration) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.clock.After(interval):
+			r.Prune()
+		}
+	}
+}
+
+func (r *MemoryTokenRegistry) isStale(h *TokenHealth, now time.Time) bool {
+	if r.staleAfter <= 0 {
+		return false
+	}
+
+	last := h.LastSuccessAt
+	if last.IsZero() {
+		last = h.RegisteredAt
+	}
+
+	return now.Sub(last) >= r.staleAfter
+}
+
+// get returns the token health adding unknown token.
+func (r *MemoryTokenRegistry) get(token string, at time.Time) *TokenHealth {
+	h, ok := r.tokens[token]
+	if !ok {
+		h = &TokenHealth{Token: token, State: TokenActive, RegisteredAt: at}
+		r.tokens[token] = h
+	}
+
+	return h
+}
@@ -0,0 +1,96 @@
+package fcm
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/oauth2"
+)
+
+type doerFunc func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error
+
+func (f doerFunc) Do(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	return f(ctx, req, resp)
+}
+
+var _ = Describe("TokenRegistry", func() {
+	var (
+		clock    *ManualClock
+		registry *MemoryTokenRegistry
+		pruned   []string
+	)
+
+	BeforeEach(func() {
+		pruned = nil
+		clock = NewManualClock(time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC))
+		registry = NewMemoryTokenRegistry(
+			WithTokenRegistryClock(clock),
+			WithTokenStaleAfter(24*time.Hour),
+			WithTokenPruneHandler(func(h TokenHealth) {
+				pruned = append(pruned, h.Token)
+			}))
+	})
+
+	It("should track send results of SimpleClient", func() {
+		var invalid []string
+		client := newClient(
+			WithEndpoint(DefaultEndpoint),
+			WithHTTPClient(doerFunc(func(_ context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+				if string(req.Body()) == `{"message":{"token":"stale"}}` {
+					resp.SetStatusCode(fasthttp.StatusNotFound)
+					resp.SetBodyString(`{"error":{"code":404,"errorDetails":[{"errorCode":"UNREGISTERED"}]}}`)
+				}
+
+				return nil
+			})),
+			WithInvalidTokenHandler(func(token string, reason error) {
+				Ω(reason).Should(Equal(ErrUnregistered))
+				invalid = append(invalid, token)
+			}),
+			WithTokenRegistry(registry),
+			WithClock(clock))
+		client.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"})
+
+		Ω(client.Send(context.Background(), &Message{Token: "fresh"})).Should(Succeed())
+		Ω(client.Send(context.Background(), &Message{Token: "stale"})).Should(Equal(ErrUnregistered))
+		Ω(client.Send(context.Background(), &Message{Topic: "news"})).Should(Succeed())
+		Ω(invalid).Should(Equal([]string{"stale"}))
+
+		health, ok := registry.Health("stale")
+		Ω(ok).Should(BeTrue())
+		Ω(health.State).Should(Equal(TokenInvalid))
+		Ω(health.Reason).Should(Equal(ErrUnregistered))
+		Ω(registry.Active()).Should(Equal([]string{"fresh"}))
+
+		fresh, ok := registry.Health("fresh")
+		Ω(ok).Should(BeTrue())
+		Ω(fresh.LastSuccessAt).Should(Equal(clock.Now()))
+		Ω(health.InvalidAt).Should(Equal(clock.Now()))
+
+		clock.Advance(24 * time.Hour)
+		registry.Prune()
+		Ω(pruned).Should(ConsistOf("stale", "fresh"))
+	})
+
+	It("should prune invalid and stale tokens", func() {
+		registry.Register("silent")
+		registry.Register("active")
+		registry.MarkInvalid("stale", ErrSenderIDMismatch, clock.Now())
+
+		clock.Advance(12 * time.Hour)
+		registry.MarkSuccess("active", clock.Now())
+		Ω(registry.Prune()).Should(HaveLen(1))
+		Ω(pruned).Should(Equal([]string{"stale"}))
+
+		clock.Advance(12 * time.Hour)
+		registry.Prune()
+		Ω(pruned).Should(Equal([]string{"stale", "silent"}))
+		Ω(registry.Active()).Should(Equal([]string{"active"}))
+
+		registry.Register("stale")
+		Ω(registry.Active()).Should(Equal([]string{"active", "stale"}))
+	})
+})
@@ -0,0 +1,129 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// MaxTopicManagementTokens is the maximum number of tokens subscribed in one request.
+const MaxTopicManagementTokens = 1000
+
+var (
+	// ErrInvalidTokens occurs if no tokens or too many tokens are passed to topic management.
+	ErrInvalidTokens = errors.New("registration tokens are invalid")
+
+	accessTokenAuthHeader  = []byte("access_token_auth")
+	accessTokenAuthHeaderV = []byte("true")
+)
+
+// TopicManagementResponse is a result of subscribing tokens to the topic
+// or unsubscribing them from the topic.
+type TopicManagementResponse struct {
+	SuccessCount int
+	FailureCount int
+	Errors       []TopicManagementError
+}
+
+// TopicManagementError describes the token failed to be subscribed or unsubscribed.
+type TopicManagementError struct {
+	// Index of the token in the request.
+	Index int
+	// Reason is an error returned by the server, e.g. "NOT_FOUND" or "INVALID_ARGUMENT".
+	Reason string
+}
+
+// SubscribeToTopic subscribes registration tokens to the topic.
+func (c *SimpleClient) SubscribeToTopic(ctx context.Context, topic string, tokens []string) (*TopicManagementResponse, error) {
+	return c.manageTopic(ctx, "/iid/v1:batchAdd", topic, tokens)
+}
+
+// UnsubscribeFromTopic unsubscribes registration tokens from the topic.
+func (c *SimpleClient) UnsubscribeFromTopic(ctx context.Context, topic string, tokens []string) (*TopicManagementResponse, error) {
+	return c.manageTopic(ctx, "/iid/v1:batchRemove", topic, tokens)
+}
+
+func (c *SimpleClient) manageTopic(ctx context.Context, path, topic string, tokens []string) (*TopicManagementResponse, error) {
+	if !c.acquire() {
+		return nil, ErrClientClosed
+	}
+
+	defer c.inflight.Done()
+
+	topic = strings.TrimPrefix(topic, topicsPrefix)
+	if !topicNameRegexp.MatchString(topic) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTopic, topic)
+	}
+
+	if len(tokens) == 0 || len(tokens) > MaxTopicManagementTokens {
+		return nil, fmt.Errorf("%w: %d tokens, expected 1 to %d", ErrInvalidTokens, len(tokens), MaxTopicManagementTokens)
+	}
+
+	if len(c.iidURL.Host) == 0 {
+		return nil, fmt.Errorf("instance id endpoint is not set")
+	}
+
+	mgmtReq := topicManagementRequest{
+		To:     topicsPrefix + topic,
+		Tokens: tokens,
+	}
+
+	body, err := mgmtReq.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	authHeaderValue, err := tokenHeaderValue(c.iidTokenSource)
+	if err != nil {
+		return nil, err
+	}
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.Header.SetMethod(fasthttp.MethodPost)
+	uri := req.URI()
+	uri.SetSchemeBytes(c.iidURL.Scheme)
+	uri.SetHostBytes(c.iidURL.Host)
+	uri.SetPath(path)
+	req.Header.SetBytesKV(contentTypeHeader, contentTypeHeaderV)
+	req.Header.SetBytesKV(authorizationHeader, authHeaderValue)
+	req.Header.SetBytesKV(accessTokenAuthHeader, accessTokenAuthHeaderV)
+	req.SetBody(body)
+
+	if err := c.client.Do(ctx, req, resp); err != nil {
+		return nil, fmt.Errorf("failed to perform request: %w", err)
+	}
+
+	return handleTopicManagementResponse(resp.StatusCode(), resp.Body())
+}
+
+func handleTopicManagementResponse(statusCode int, respBody []byte) (*TopicManagementResponse, error) {
+	var resp topicManagementResponse
+	if err := resp.UnmarshalJSON(respBody); err != nil {
+		return nil, fmt.Errorf("unmarshal topic management response with status code %d: %w", statusCode, err)
+	}
+
+	if statusCode < 200 || statusCode > 299 || resp.Error != "" {
+		return nil, fmt.Errorf("unsuccessful topic management response with status code: %d: %s", statusCode, string(respBody))
+	}
+
+	var result TopicManagementResponse
+	for i, r := range resp.Results {
+		if r.Error == "" {
+			result.SuccessCount++
+			continue
+		}
+
+		result.FailureCount++
+		result.Errors = append(result.Errors, TopicManagementError{Index: i, Reason: r.Error})
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,81 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/oauth2"
+)
+
+var _ = Describe("SimpleClient topic management", func() {
+	var (
+		client   *SimpleClient
+		lastReq  *fasthttp.Request
+		respBody string
+	)
+
+	BeforeEach(func() {
+		lastReq = fasthttp.AcquireRequest()
+		respBody = `{"results":[{},{"error":"NOT_FOUND"}]}`
+		client = newClient(
+			WithEndpoint(DefaultEndpoint),
+			WithIIDEndpoint(DefaultIIDEndpoint),
+			WithHTTPClient(doerFunc(func(_ context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+				req.CopyTo(lastReq)
+				resp.SetBodyString(respBody)
+				return nil
+			})))
+		client.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "jwt"})
+		client.iidTokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"})
+	})
+
+	AfterEach(func() {
+		fasthttp.ReleaseRequest(lastReq)
+	})
+
+	It("should subscribe tokens to topic", func() {
+		resp, err := client.SubscribeToTopic(context.Background(), "/topics/news", []string{"a", "b"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(resp).Should(Equal(&TopicManagementResponse{
+			SuccessCount: 1,
+			FailureCount: 1,
+			Errors:       []TopicManagementError{{Index: 1, Reason: "NOT_FOUND"}},
+		}))
+
+		Ω(string(lastReq.URI().FullURI())).Should(Equal("https://iid.googleapis.com/iid/v1:batchAdd"))
+		Ω(string(lastReq.Header.Peek("access_token_auth"))).Should(Equal("true"))
+		Ω(string(lastReq.Header.Peek("Authorization"))).Should(Equal("Bearer access"))
+		Ω(string(lastReq.Body())).Should(MatchJSON(`{"to":"/topics/news","registration_tokens":["a","b"]}`))
+	})
+
+	It("should unsubscribe tokens from topic", func() {
+		respBody = `{"results":[{}]}`
+		resp, err := client.UnsubscribeFromTopic(context.Background(), "news", []string{"a"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(resp.SuccessCount).Should(Equal(1))
+		Ω(string(lastReq.URI().Path())).Should(Equal("/iid/v1:batchRemove"))
+	})
+
+	It("should fail on server error", func() {
+		respBody = `{"error":"InvalidToken"}`
+		_, err := client.SubscribeToTopic(context.Background(), "news", []string{"a"})
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("should validate arguments", func() {
+		_, err := client.SubscribeToTopic(context.Background(), "bad topic", []string{"a"})
+		Ω(errors.Is(err, ErrInvalidTopic)).Should(BeTrue())
+
+		_, err = client.SubscribeToTopic(context.Background(), "news", nil)
+		Ω(errors.Is(err, ErrInvalidTokens)).Should(BeTrue())
+	})
+
+	It("should send validate only request on dry run", func() {
+		respBody = `{}`
+		Ω(client.SendDryRun(context.Background(), &Message{Token: "a"})).Should(Succeed())
+		Ω(string(lastReq.Body())).Should(MatchJSON(`{"validate_only":true,"message":{"token":"a"}}`))
+	})
+})
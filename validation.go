@@ -0,0 +1,289 @@
+package fcm
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrMultipleTargets occurs if more than one of token, topic or condition is set.
+	ErrMultipleTargets = errors.New("only one of token, topic or condition must be set")
+
+	// ErrInvalidTopic occurs if message topic name is malformed.
+	ErrInvalidTopic = errors.New("topic name is invalid")
+
+	// ErrReservedDataKey occurs if data payload uses a key reserved by FCM.
+	ErrReservedDataKey = errors.New("data key is reserved")
+
+	// ErrPayloadTooLarge occurs if message payload exceeds MaxPayloadSize.
+	ErrPayloadTooLarge = errors.New("payload is too large")
+
+	// ErrInvalidDuration occurs if duration is not in protobuf Duration format, e.g. "3.5s".
+	ErrInvalidDuration = errors.New("duration is invalid")
+
+	// ErrInvalidEnum occurs if enum field has unknown value.
+	ErrInvalidEnum = errors.New("value is not allowed")
+
+	// ErrInvalidValue occurs if numeric field is out of the allowed range.
+	ErrInvalidValue = errors.New("value is out of range")
+
+	// ErrInvalidColor occurs if color is not in #rrggbb format or its components are out of range.
+	ErrInvalidColor = errors.New("color is invalid")
+
+	// ErrInvalidURL occurs if URL field is not an absolute http(s) URL.
+	ErrInvalidURL = errors.New("url is invalid")
+
+	// ErrMissingField occurs if field required by FCM is empty.
+	ErrMissingField = errors.New("field is required")
+
+	reservedDataKey = []string{"from", "message_type"}
+	reservedDataPfx = []string{"google", "gcm"}
+)
+
+// ValidationError describes a single violation of the message constraints.
+// Path is a JSON path of the invalid field, e.g. "message.android.ttl".
+type ValidationError struct {
+	Path string
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors lists every violation found in the message.
+// errors.Is and errors.As match any of the listed errors.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (e ValidationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e ValidationErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validator collects violations found in the message.
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(path string, err error) {
+	v.errs = append(v.errs, &ValidationError{Path: path, Err: err})
+}
+
+func (v *validator) addf(path string, err error, format string, args ...interface{}) {
+	v.add(path, fmt.Errorf("%w: "+format, append([]interface{}{err}, args...)...))
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return v.errs
+}
+
+func (v *validator) message(msg *Message) {
+	targets := 0
+	for _, target := range []string{msg.Token, msg.Topic, msg.Condition} {
+		if target != "" {
+			targets++
+		}
+	}
+
+	switch {
+	case targets == 0:
+		v.add("message", ErrInvalidTarget)
+	case targets > 1:
+		v.add("message", ErrMultipleTargets)
+	}
+
+	if msg.Topic != "" && !topicNameRegexp.MatchString(strings.TrimPrefix(msg.Topic, topicsPrefix)) {
+		v.addf("message.topic", ErrInvalidTopic, "%q", msg.Topic)
+	}
+
+	if msg.Condition != "" {
+		if err := ValidateCondition(msg.Condition); err != nil {
+			v.add("message.condition", err)
+		}
+	}
+
+	v.data("message.data", msg.Data)
+
+	if msg.Notification != nil {
+		v.url("message.notification.image", msg.Notification.Image)
+	}
+
+	if msg.Android != nil {
+		v.android("message.android", msg.Android)
+	}
+
+	if size := msg.PayloadSize(); size > MaxPayloadSize {
+		v.addf("message", ErrPayloadTooLarge, "%d bytes, at most %d are allowed", size, MaxPayloadSize)
+	}
+}
+
+func (v *validator) android(path string, cfg *AndroidConfig) {
+	switch cfg.Priority {
+	case "", AndroidMessagePriorityNormal, AndroidMessagePriorityHigh:
+	default:
+		v.addf(path+".priority", ErrInvalidEnum, "%q", cfg.Priority)
+	}
+
+	if cfg.Ttl != nil && (*cfg.Ttl < 0 || cfg.Ttl.Duration() > MaxTTL) {
+		v.addf(path+".ttl", ErrInvalidDuration, "%s is out of [0s, %s] range", cfg.Ttl, Duration(MaxTTL))
+	}
+
+	v.data(path+".data", cfg.Data)
+
+	if cfg.Notification != nil {
+		v.androidNotification(path+".notification", cfg.Notification)
+	}
+}
+
+func (v *validator) androidNotification(path string, n *AndroidNotification) {
+	if n.Color != "" {
+		if _, err := ParseColor(n.Color); err != nil {
+			v.add(path+".color", err)
+		}
+	}
+
+	switch n.NotificationPriority {
+	case "", NotificationPriorityUnspecified, NotificationPriorityMin, NotificationPriorityLow,
+		NotificationPriorityDefault, NotificationPriorityHigh, NotificationPriorityMax:
+	default:
+		v.addf(path+".notification_priority", ErrInvalidEnum, "%q", n.NotificationPriority)
+	}
+
+	switch n.Visibility {
+	case "", VisibilityUnspecified, VisibilityPrivate, VisibilityPublic, VisibilitySecret:
+	default:
+		v.addf(path+".visibility", ErrInvalidEnum, "%q", n.Visibility)
+	}
+
+	if n.NotificationCount < 0 {
+		v.addf(path+".notification_count", ErrInvalidValue, "negative value %d", n.NotificationCount)
+	}
+
+	for i, timing := range n.VibrateTimings {
+		v.duration(fmt.Sprintf("%s.vibrate_timings[%d]", path, i), timing)
+	}
+
+	v.url(path+".image", n.Image)
+
+	if n.LightSettings != nil {
+		v.lightSettings(path+".light_settings", n.LightSettings)
+	}
+}
+
+func (v *validator) lightSettings(path string, ls *LightSettings) {
+	v.color(path+".color", ls.Color)
+
+	for _, d := range []struct {
+		name  string
+		value *Duration
+	}{
+		{"light_on_duration", ls.LightOnDuration},
+		{"light_off_duration", ls.LightOffDuration},
+	} {
+		if d.value == nil {
+			v.add(path+"."+d.name, ErrMissingField)
+			continue
+		}
+
+		v.duration(path+"."+d.name, *d.value)
+	}
+}
+
+func (v *validator) data(path string, data map[string]string) {
+	// keys are sorted to report errors in stable order
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if isReservedDataKey(key) {
+			v.addf(path+"."+key, ErrReservedDataKey, "%q", key)
+		}
+	}
+}
+
+func (v *validator) color(path string, c Color) {
+	for _, comp := range []struct {
+		name  string
+		value float32
+	}{
+		{"red", c.Red},
+		{"green", c.Green},
+		{"blue", c.Blue},
+		{"alpha", c.Alpha},
+	} {
+		if comp.value < 0 || comp.value > 1 {
+			v.addf(path+"."+comp.name, ErrInvalidColor, "%v is out of [0, 1] range", comp.value)
+		}
+	}
+}
+
+func (v *validator) duration(path string, d Duration) {
+	if d < 0 {
+		v.addf(path, ErrInvalidDuration, "negative value %s", d)
+	}
+}
+
+func (v *validator) url(path, rawURL string) {
+	if rawURL == "" {
+		return
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.addf(path, ErrInvalidURL, "%q", rawURL)
+	}
+}
+
+func isReservedDataKey(key string) bool {
+	for _, reserved := range reservedDataKey {
+		if key == reserved {
+			return true
+		}
+	}
+
+	for _, prefix := range reservedDataPfx {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,146 @@
+package fcm
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Message validation", func() {
+	It("should fail on nil message", func() {
+		var msg *Message
+		Ω(msg.Validate()).Should(Equal(ErrInvalidMessage))
+	})
+
+	table.DescribeTable("valid messages",
+		func(msg *Message) {
+			Ω(msg.Validate()).Should(Succeed())
+		},
+		table.Entry("token", &Message{Token: "token"}),
+		table.Entry("topic", &Message{Topic: "news"}),
+		table.Entry("prefixed topic", &Message{Topic: "/topics/news"}),
+		table.Entry("condition", &Message{Condition: "'news' in topics"}),
+		table.Entry("full android config", &Message{
+			Token: "token",
+			Data:  map[string]string{"key": "value"},
+			Notification: &Notification{
+				Title: "title",
+				Image: "https://example.com/image.png",
+			},
+			Android: &AndroidConfig{
+				Priority: AndroidMessagePriorityHigh,
+				Ttl:      NewDuration(3500 * time.Millisecond),
+				Notification: &AndroidNotification{
+					Color:                "#00ff00",
+					NotificationPriority: NotificationPriorityMax,
+					Visibility:           VisibilityPublic,
+					VibrateTimings:       []Duration{Duration(time.Second / 2), Duration(time.Second)},
+					LightSettings: &LightSettings{
+						Color:            Color{Red: 1, Alpha: 0.5},
+						LightOnDuration:  NewDuration(time.Second),
+						LightOffDuration: NewDuration(0),
+					},
+				},
+			},
+		}),
+	)
+
+	table.DescribeTable("invalid messages",
+		func(msg *Message, path string, expectedErr error) {
+			err := msg.Validate()
+			Ω(errors.Is(err, expectedErr)).Should(BeTrue(), err.Error())
+
+			var validationErrs ValidationErrors
+			Ω(errors.As(err, &validationErrs)).Should(BeTrue())
+			Ω(validationErrs).Should(HaveLen(1))
+			Ω(validationErrs[0].Path).Should(Equal(path))
+		},
+		table.Entry("no target", &Message{}, "message", ErrInvalidTarget),
+		table.Entry("token and topic", &Message{Token: "token", Topic: "news"}, "message", ErrMultipleTargets),
+		table.Entry("invalid topic", &Message{Topic: "/topics/bad news"}, "message.topic", ErrInvalidTopic),
+		table.Entry("invalid condition", &Message{Condition: "'news'"}, "message.condition", ErrInvalidCondition),
+		table.Entry("reserved data key", &Message{Token: "token", Data: map[string]string{"from": "x"}},
+			"message.data.from", ErrReservedDataKey),
+		table.Entry("reserved data prefix", &Message{Token: "token", Android: &AndroidConfig{
+			Data: map[string]string{"google.key": "x"},
+		}}, "message.android.data.google.key", ErrReservedDataKey),
+		table.Entry("payload too large", &Message{Token: "token", Data: map[string]string{
+			"key": strings.Repeat("x", MaxPayloadSize),
+		}}, "message", ErrPayloadTooLarge),
+		table.Entry("ttl over maximum", &Message{Token: "token", Android: &AndroidConfig{Ttl: NewDuration(MaxTTL + 1)}},
+			"message.android.ttl", ErrInvalidDuration),
+		table.Entry("negative ttl", &Message{Token: "token", Android: &AndroidConfig{Ttl: NewDuration(-time.Second)}},
+			"message.android.ttl", ErrInvalidDuration),
+		table.Entry("invalid priority", &Message{Token: "token", Android: &AndroidConfig{Priority: "high"}},
+			"message.android.priority", ErrInvalidEnum),
+		table.Entry("invalid visibility", &Message{Token: "token", Android: &AndroidConfig{
+			Notification: &AndroidNotification{Visibility: "HIDDEN"},
+		}}, "message.android.notification.visibility", ErrInvalidEnum),
+		table.Entry("negative notification count", &Message{Token: "token", Android: &AndroidConfig{
+			Notification: &AndroidNotification{NotificationCount: -1},
+		}}, "message.android.notification.notification_count", ErrInvalidValue),
+		table.Entry("invalid color", &Message{Token: "token", Android: &AndroidConfig{
+			Notification: &AndroidNotification{Color: "red"},
+		}}, "message.android.notification.color", ErrInvalidColor),
+		table.Entry("invalid light color", &Message{Token: "token", Android: &AndroidConfig{
+			Notification: &AndroidNotification{LightSettings: &LightSettings{
+				Color:            Color{Red: 1, Alpha: 2},
+				LightOnDuration:  NewDuration(time.Second),
+				LightOffDuration: NewDuration(time.Second),
+			}},
+		}}, "message.android.notification.light_settings.color.alpha", ErrInvalidColor),
+		table.Entry("invalid image url", &Message{Token: "token", Notification: &Notification{Image: "image.png"}},
+			"message.notification.image", ErrInvalidURL),
+		table.Entry("invalid vibrate timing", &Message{Token: "token", Android: &AndroidConfig{
+			Notification: &AndroidNotification{VibrateTimings: []Duration{Duration(time.Second), Duration(-time.Second)}},
+		}}, "message.android.notification.vibrate_timings[1]", ErrInvalidDuration),
+		table.Entry("missing light duration", &Message{Token: "token", Android: &AndroidConfig{
+			Notification: &AndroidNotification{LightSettings: &LightSettings{LightOnDuration: NewDuration(time.Second)}},
+		}}, "message.android.notification.light_settings.light_off_duration", ErrMissingField),
+	)
+
+	It("should list every violation", func() {
+		msg := &Message{
+			Token: "token",
+			Topic: "news",
+			Data:  map[string]string{"gcm.key": "x"},
+			Android: &AndroidConfig{
+				Ttl:          NewDuration(-time.Hour),
+				Notification: &AndroidNotification{Color: "#fff"},
+			},
+		}
+
+		var validationErrs ValidationErrors
+		Ω(errors.As(msg.Validate(), &validationErrs)).Should(BeTrue())
+
+		paths := make([]string, 0, len(validationErrs))
+		for _, err := range validationErrs {
+			paths = append(paths, err.Path)
+		}
+
+		Ω(paths).Should(ConsistOf(
+			"message",
+			"message.data.gcm.key",
+			"message.android.ttl",
+			"message.android.notification.color",
+		))
+	})
+
+	It("should report data keys in stable order", func() {
+		msg := &Message{
+			Token: "token",
+			Data:  map[string]string{"google.c": "x", "from": "x", "gcm.b": "x", "google.a": "x"},
+		}
+
+		for i := 0; i < 10; i++ {
+			Ω(msg.Validate().Error()).Should(Equal(`message.data.from: data key is reserved: "from"; ` +
+				`message.data.gcm.b: data key is reserved: "gcm.b"; ` +
+				`message.data.google.a: data key is reserved: "google.a"; ` +
+				`message.data.google.c: data key is reserved: "google.c"`))
+		}
+	})
+})